@@ -0,0 +1,72 @@
+package kettlecycletest
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// parseAcceptanceOverride decodes the "acceptance" field of a start_capture
+// command (typically a map[string]interface{} decoded from JSON-over-gRPC)
+// into an AcceptanceCriteria.
+func parseAcceptanceOverride(raw interface{}) (*AcceptanceCriteria, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling acceptance override: %w", err)
+	}
+	var ac AcceptanceCriteria
+	if err := json.Unmarshal(data, &ac); err != nil {
+		return nil, fmt.Errorf("unmarshaling acceptance override: %w", err)
+	}
+	return &ac, nil
+}
+
+// AcceptanceCriteria defines the pass/fail bounds a trial's statistics are
+// checked against. A zero value for a given field means that criterion is
+// not enforced.
+type AcceptanceCriteria struct {
+	MinPeakForce                        float64 `json:"min_peak_force,omitempty"`
+	MaxPeakForce                        float64 `json:"max_peak_force,omitempty"`
+	MinImpulse                          float64 `json:"min_impulse,omitempty"`
+	MaxImpulse                          float64 `json:"max_impulse,omitempty"`
+	MinDwellMs                          float64 `json:"min_dwell_ms,omitempty"`
+	MaxRiseMs                           float64 `json:"max_rise_ms,omitempty"`
+	MaxSamplesBelowThresholdDuringDwell int     `json:"max_samples_below_threshold_during_dwell,omitempty"`
+}
+
+// evaluate checks stats against the criteria and returns "pass"/"fail" plus
+// a description of every violated criterion. A nil receiver always passes.
+func (ac *AcceptanceCriteria) evaluate(stats forceStats) (string, []string) {
+	if ac == nil {
+		return "pass", nil
+	}
+
+	var failures []string
+	if ac.MinPeakForce > 0 && stats.max < ac.MinPeakForce {
+		failures = append(failures, fmt.Sprintf("peak_force=%.2f < min_peak_force=%.2f", stats.max, ac.MinPeakForce))
+	}
+	if ac.MaxPeakForce > 0 && stats.max > ac.MaxPeakForce {
+		failures = append(failures, fmt.Sprintf("peak_force=%.2f > max_peak_force=%.2f", stats.max, ac.MaxPeakForce))
+	}
+	if ac.MinImpulse > 0 && stats.impulse < ac.MinImpulse {
+		failures = append(failures, fmt.Sprintf("impulse=%.2f < min_impulse=%.2f", stats.impulse, ac.MinImpulse))
+	}
+	if ac.MaxImpulse > 0 && stats.impulse > ac.MaxImpulse {
+		failures = append(failures, fmt.Sprintf("impulse=%.2f > max_impulse=%.2f", stats.impulse, ac.MaxImpulse))
+	}
+	if ac.MinDwellMs > 0 && stats.dwellMs < ac.MinDwellMs {
+		failures = append(failures, fmt.Sprintf("dwell_time_ms=%.2f < min_dwell_ms=%.2f", stats.dwellMs, ac.MinDwellMs))
+	}
+	if ac.MaxRiseMs > 0 && stats.riseTimeMs > ac.MaxRiseMs {
+		failures = append(failures, fmt.Sprintf("rise_time_ms=%.2f > max_rise_ms=%.2f", stats.riseTimeMs, ac.MaxRiseMs))
+	}
+	if ac.MaxSamplesBelowThresholdDuringDwell > 0 && stats.samplesBelowDuringDwell > ac.MaxSamplesBelowThresholdDuringDwell {
+		failures = append(failures, fmt.Sprintf(
+			"samples_below_threshold_during_dwell=%d > max_samples_below_threshold_during_dwell=%d",
+			stats.samplesBelowDuringDwell, ac.MaxSamplesBelowThresholdDuringDwell))
+	}
+
+	if len(failures) > 0 {
+		return "fail", failures
+	}
+	return "pass", nil
+}
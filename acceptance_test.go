@@ -0,0 +1,54 @@
+package kettlecycletest
+
+import "testing"
+
+func TestAcceptanceCriteria_Evaluate(t *testing.T) {
+	t.Run("nil criteria always passes", func(t *testing.T) {
+		var ac *AcceptanceCriteria
+		verdict, failures := ac.evaluate(forceStats{max: 1})
+		if verdict != "pass" || failures != nil {
+			t.Errorf("expected pass with no failures, got %v %v", verdict, failures)
+		}
+	})
+
+	t.Run("passes when all criteria satisfied", func(t *testing.T) {
+		ac := &AcceptanceCriteria{MinPeakForce: 100, MaxPeakForce: 300}
+		verdict, failures := ac.evaluate(forceStats{max: 200})
+		if verdict != "pass" || len(failures) != 0 {
+			t.Errorf("expected pass, got %v %v", verdict, failures)
+		}
+	})
+
+	t.Run("fails below min_peak_force", func(t *testing.T) {
+		ac := &AcceptanceCriteria{MinPeakForce: 200}
+		verdict, failures := ac.evaluate(forceStats{max: 180})
+		if verdict != "fail" {
+			t.Errorf("expected fail, got %v", verdict)
+		}
+		if len(failures) != 1 {
+			t.Fatalf("expected 1 failure, got %v", failures)
+		}
+	})
+
+	t.Run("reports every violated criterion", func(t *testing.T) {
+		ac := &AcceptanceCriteria{MinPeakForce: 200, MaxImpulse: 10}
+		verdict, failures := ac.evaluate(forceStats{max: 180, impulse: 20})
+		if verdict != "fail" {
+			t.Errorf("expected fail, got %v", verdict)
+		}
+		if len(failures) != 2 {
+			t.Errorf("expected 2 failures, got %v", failures)
+		}
+	})
+}
+
+func TestParseAcceptanceOverride(t *testing.T) {
+	raw := map[string]interface{}{"min_peak_force": 150.0}
+	ac, err := parseAcceptanceOverride(raw)
+	if err != nil {
+		t.Fatalf("parseAcceptanceOverride failed: %v", err)
+	}
+	if ac.MinPeakForce != 150.0 {
+		t.Errorf("expected MinPeakForce=150, got %v", ac.MinPeakForce)
+	}
+}
@@ -0,0 +1,40 @@
+package kettlecycletest
+
+import (
+	"math/rand"
+	"time"
+)
+
+// clock abstracts time.Now and time.After so cycleLoop's pacing and failure
+// backoff can be driven by a fakeClock in tests instead of real wall-clock
+// sleeps.
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the production clock, backed directly by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// backoffDuration returns the sleep duration for the nth consecutive failure
+// (n >= 1): base*2^(n-1), capped at max, with +/-20% jitter applied so
+// multiple kettles failing together don't retry in lockstep.
+func backoffDuration(base, max time.Duration, n int) time.Duration {
+	d := base
+	for i := 1; i < n && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	jitter := time.Duration((rand.Float64()*0.4 - 0.2) * float64(d))
+	d += jitter
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
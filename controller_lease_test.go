@@ -0,0 +1,115 @@
+package kettlecycletest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+
+	"kettlecycletest/lease"
+)
+
+func TestHandleExecuteCycle_RefusesWithoutLease(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+	deps, cfg := testDeps()
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+
+	fake := lease.NewFake()
+	fake.SimulateContention(errors.New("held by another instance"))
+	kctrl.lease = fake
+	kctrl.leaseHeld = false
+
+	_, err = kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "execute_cycle"})
+	if err == nil {
+		t.Fatal("expected execute_cycle to refuse to run without the lease held")
+	}
+}
+
+func TestHandleStart_FailsWhenLeaseContended(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+	deps, cfg := testDeps()
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+
+	fake := lease.NewFake()
+	contentionErr := errors.New("held by another instance")
+	fake.SimulateContention(contentionErr)
+	kctrl.lease = fake
+	kctrl.leaseHeld = false
+
+	_, err = kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "start"})
+	if err == nil {
+		t.Fatal("expected start to fail when the lease is contended")
+	}
+	if kctrl.activeTrial != nil {
+		t.Fatal("expected no active trial after a contended lease")
+	}
+}
+
+func TestCycleLoop_LosesLeadershipOnRenewFailure(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+	deps, cfg := testDeps()
+	cfg.MinCycleIntervalMs = 10
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+
+	fake := lease.NewFake()
+	kctrl.lease = fake
+	kctrl.leaseHeld = false
+	kctrl.leaseRenewInterval = time.Millisecond
+
+	if _, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "start"}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	fake.SimulateLoss(errors.New("lease taken over by another instance"))
+
+	state := waitForTerminalState(t, kctrl, 5*time.Second)
+	if state["state"] != "lost_leadership" {
+		t.Fatalf("expected state=lost_leadership after a failed renewal, got %v", state["state"])
+	}
+	if fake.Held() {
+		t.Error("expected the lease to no longer be held after losing leadership")
+	}
+	if got := testutil.ToFloat64(kctrl.metrics.kettleTrialActive.WithLabelValues(name.Name)); got != 0 {
+		t.Errorf("expected kettle_trial_active=0 after losing leadership, got %v", got)
+	}
+
+	// Clear the simulated loss and resume: handleResume should re-acquire
+	// the lease and restart cycling.
+	fake.SimulateLoss(nil)
+	trialID := state["trial_id"]
+	result, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "resume"})
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if result["trial_id"] != trialID {
+		t.Errorf("resume trial_id = %v, want %v", result["trial_id"], trialID)
+	}
+	if !fake.Held() {
+		t.Error("expected the lease to be held again after resume")
+	}
+
+	kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "abandon_trial"})
+}
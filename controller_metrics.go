@@ -0,0 +1,132 @@
+package kettlecycletest
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.viam.com/rdk/logging"
+)
+
+// controllerMetrics bundles every controller-level Prometheus collector.
+//
+// Cycles and trials are labeled by controller (the resource name), not by
+// trial_id: trial IDs are unique per run and would give these metrics
+// unbounded cardinality, the same reason force_metrics.go labels by sensor
+// rather than by capture.
+type controllerMetrics struct {
+	kettleCyclesTotal          *prometheus.CounterVec
+	kettleCycleDuration        *prometheus.HistogramVec
+	kettleArmWaitSeconds       *prometheus.HistogramVec
+	kettleTrialActive          *prometheus.GaugeVec
+	kettleForcePeakNewtons     *prometheus.GaugeVec
+	kettleForceMeanNewtons     *prometheus.GaugeVec
+	kettleForceCaptureDuration *prometheus.HistogramVec
+	kettleEventsDropped        *prometheus.CounterVec
+}
+
+// newControllerMetrics creates a fresh set of controller-level collectors and
+// registers them to reg. Production uses defaultControllerMetrics, registered
+// to the process-wide metricsRegistry; tests that want isolation from other
+// tests' accumulated counter state can build their own with a fresh
+// *prometheus.Registry instead.
+func newControllerMetrics(reg *prometheus.Registry) *controllerMetrics {
+	m := &controllerMetrics{
+		kettleCyclesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "kettle_cycles_total",
+			Help: "Cycles executed, labeled by controller and result (success/failure).",
+		}, []string{"controller", "result"}),
+
+		kettleCycleDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "kettle_cycle_duration_seconds",
+			Help:    "Distribution of execute_cycle duration.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"controller"}),
+
+		kettleArmWaitSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "kettle_arm_wait_seconds",
+			Help:    "Distribution of time spent in waitForArmStopped per cycle.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"controller"}),
+
+		kettleTrialActive: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "kettle_trial_active",
+			Help: "1 while a scheduled trial is actively cycling, 0 otherwise.",
+		}, []string{"controller"}),
+
+		kettleForcePeakNewtons: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "kettle_force_peak_newtons",
+			Help: "Peak force from the most recently completed cycle's force capture, when a force sensor is configured.",
+		}, []string{"controller"}),
+
+		kettleForceMeanNewtons: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "kettle_force_mean_newtons",
+			Help: "Mean force from the most recently completed cycle's force capture, when a force sensor is configured.",
+		}, []string{"controller"}),
+
+		kettleForceCaptureDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "kettle_force_capture_duration_seconds",
+			Help:    "Distribution of force capture duration as seen by a program's end_force_capture step.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"controller"}),
+
+		kettleEventsDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "kettle_events_dropped_total",
+			Help: "Lifecycle events dropped from the in-memory ring sink because it was full.",
+		}, []string{"controller"}),
+	}
+
+	reg.MustRegister(
+		m.kettleCyclesTotal,
+		m.kettleCycleDuration,
+		m.kettleArmWaitSeconds,
+		m.kettleTrialActive,
+		m.kettleForcePeakNewtons,
+		m.kettleForceMeanNewtons,
+		m.kettleForceCaptureDuration,
+		m.kettleEventsDropped,
+	)
+
+	return m
+}
+
+// defaultControllerMetrics is registered to the process-wide metricsRegistry
+// so every controller instance in the module is scrapeable from one
+// /metrics endpoint instead of colliding on duplicate registration.
+var defaultControllerMetrics = newControllerMetrics(metricsRegistry)
+
+// forceSamplesDropped is a force-sensor-level metric (labeled by sensor, not
+// controller), but lives here because it predates force_metrics.go's own
+// registry; every forceSensor instance shares it regardless of which
+// controller, if any, is polling it.
+var forceSamplesDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "kettle_force_samples_dropped_total",
+	Help: "Samples dropped from a subscription's ring buffer because a poller fell behind.",
+}, []string{"sensor"})
+
+func init() {
+	metricsRegistry.MustRegister(forceSamplesDropped)
+}
+
+// startControllerMetricsServer starts an HTTP server exposing reg on addr,
+// returning nil if addr is empty. It is owned by a single controller
+// instance and returned so Close can shut it down gracefully, the same
+// pattern force_metrics.go's startMetricsServer uses for forceSensor.
+func startControllerMetricsServer(addr string, reg *prometheus.Registry, logger logging.Logger) *http.Server {
+	if addr == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("controller metrics server on %s stopped: %v", addr, err)
+		}
+	}()
+	logger.Infof("controller metrics server listening on %s", addr)
+
+	return srv
+}
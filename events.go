@@ -0,0 +1,314 @@
+package kettlecycletest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	defaultEventRingCap = 500
+
+	webhookMaxAttempts = 3
+	webhookBackoffBase = 200 * time.Millisecond
+)
+
+// EventSink receives every event published by an eventBroadcaster, in
+// publish order. HandleEvent runs on the publisher's goroutine, so a slow
+// sink delays Publish: the webhook sink hands delivery off to its own
+// goroutine, while the file sink writes each event synchronously.
+type EventSink interface {
+	HandleEvent(event map[string]interface{})
+}
+
+// eventBroadcaster fans trial lifecycle events out to a set of sinks,
+// modeled on the event recorder used by Kubernetes controllers: callers
+// publish a type and a bag of fields, and the broadcaster stamps a
+// monotonically increasing sequence number and timestamp before handing the
+// record to each sink. Publish does not wait on webhook delivery (the
+// webhook sink dispatches on its own goroutine) and the ringEventSink drops
+// oldest once full, but it does wait on the file sink's synchronous write.
+type eventBroadcaster struct {
+	mu      sync.Mutex
+	sinks   []EventSink
+	nextSeq int64
+}
+
+func newEventBroadcaster() *eventBroadcaster {
+	return &eventBroadcaster{}
+}
+
+func (b *eventBroadcaster) addSink(sink EventSink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sinks = append(b.sinks, sink)
+}
+
+// Publish builds an event record (seq, type, time, plus fields) and hands it
+// to every registered sink.
+func (b *eventBroadcaster) Publish(eventType string, fields map[string]interface{}) {
+	b.mu.Lock()
+	b.nextSeq++
+	event := map[string]interface{}{
+		"seq":  b.nextSeq,
+		"type": eventType,
+		"time": time.Now().Format(time.RFC3339Nano),
+	}
+	for k, v := range fields {
+		event[k] = v
+	}
+	sinks := make([]EventSink, len(b.sinks))
+	copy(sinks, b.sinks)
+	b.mu.Unlock()
+
+	for _, sink := range sinks {
+		sink.HandleEvent(event)
+	}
+}
+
+// Flush flushes every sink that supports it (the file sink), logging but not
+// returning per-sink failures -- a flush problem on Close must not prevent
+// the other sinks or the rest of Close from running.
+func (b *eventBroadcaster) Flush() {
+	b.mu.Lock()
+	sinks := make([]EventSink, len(b.sinks))
+	copy(sinks, b.sinks)
+	b.mu.Unlock()
+
+	for _, sink := range sinks {
+		if f, ok := sink.(interface{ Flush() error }); ok {
+			if err := f.Flush(); err != nil {
+				// Sinks log their own warnings via their own logger; nothing
+				// further to do here.
+				_ = err
+			}
+		}
+	}
+}
+
+// ringEventSink keeps the most recent capacity events in memory, queryable
+// via Since, and drops the oldest once full. Dropped events are counted in
+// eventsDropped so the drop rate is observable.
+type ringEventSink struct {
+	controller    string
+	capacity      int
+	eventsDropped *prometheus.CounterVec
+
+	mu     sync.Mutex
+	events []map[string]interface{}
+}
+
+func newRingEventSink(controller string, capacity int, eventsDropped *prometheus.CounterVec) *ringEventSink {
+	if capacity <= 0 {
+		capacity = defaultEventRingCap
+	}
+	return &ringEventSink{controller: controller, capacity: capacity, eventsDropped: eventsDropped}
+}
+
+func (r *ringEventSink) HandleEvent(event map[string]interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.events) >= r.capacity {
+		r.events = r.events[1:]
+		r.eventsDropped.WithLabelValues(r.controller).Inc()
+	}
+	r.events = append(r.events, event)
+}
+
+// Since returns events with seq > sinceSeq, oldest first, capped at limit (0
+// = no cap).
+func (r *ringEventSink) Since(sinceSeq int64, limit int) []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var matched []map[string]interface{}
+	for _, e := range r.events {
+		seq, _ := e["seq"].(int64)
+		if seq > sinceSeq {
+			matched = append(matched, e)
+		}
+	}
+	if limit > 0 && len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+	return matched
+}
+
+// eventFileSink appends one JSON object per event to a size-rotated file,
+// the same way trial_store.go's trialStore and trial_log.go's trialLogger
+// do. It is intentionally a third copy of that rotation logic rather than a
+// shared abstraction: the three rotate independent files with independent
+// lifecycles, and extracting one now would mean refactoring the other two
+// just to land this request.
+type eventFileSink struct {
+	logger   logging.Logger
+	path     string
+	maxBytes int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newEventFileSink(path string, logger logging.Logger) (*eventFileSink, error) {
+	s := &eventFileSink{
+		logger:   logger,
+		path:     path,
+		maxBytes: defaultTrialStoreMaxBytes,
+		maxFiles: defaultTrialStoreMaxFiles,
+	}
+	if err := s.openLocked(); err != nil {
+		return nil, fmt.Errorf("opening event log %q: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *eventFileSink) openLocked() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.file = f
+	s.size = info.Size()
+	return nil
+}
+
+func (s *eventFileSink) HandleEvent(event map[string]interface{}) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Warnf("event log: failed to marshal event: %v", err)
+		return
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file != nil && s.size+int64(len(data)) > s.maxBytes {
+		if err := s.rotateLocked(); err != nil {
+			s.logger.Warnf("event log: rotation failed: %v", err)
+		}
+	}
+	if s.file == nil {
+		s.logger.Warnf("event log: no open file, dropping event")
+		return
+	}
+
+	n, err := s.file.Write(data)
+	if err != nil {
+		s.logger.Warnf("event log: write failed: %v", err)
+		return
+	}
+	s.size += int64(n)
+}
+
+func (s *eventFileSink) rotateLocked() error {
+	if s.file != nil {
+		s.file.Close()
+		s.file = nil
+	}
+
+	oldest := fmt.Sprintf("%s.%d", s.path, s.maxFiles)
+	os.Remove(oldest)
+
+	for i := s.maxFiles - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", s.path, i)
+		dst := fmt.Sprintf("%s.%d", s.path, i+1)
+		if _, err := os.Stat(src); err == nil {
+			os.Rename(src, dst)
+		}
+	}
+	if _, err := os.Stat(s.path); err == nil {
+		os.Rename(s.path, s.path+".1")
+	}
+
+	return s.openLocked()
+}
+
+// Flush fsyncs the active file; called from eventBroadcaster.Flush on Close.
+func (s *eventFileSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	if err := s.file.Sync(); err != nil {
+		s.logger.Warnf("event log: flush failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *eventFileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// webhookEventSink POSTs each event as JSON to url, retrying with backoff on
+// failure. Dispatch happens on its own goroutine per event so a slow or down
+// endpoint never blocks the publisher.
+type webhookEventSink struct {
+	url    string
+	logger logging.Logger
+	client *http.Client
+}
+
+func newWebhookEventSink(url string, logger logging.Logger) *webhookEventSink {
+	return &webhookEventSink{
+		url:    url,
+		logger: logger,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (w *webhookEventSink) HandleEvent(event map[string]interface{}) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		w.logger.Warnf("event webhook: failed to marshal event: %v", err)
+		return
+	}
+	go w.deliver(data)
+}
+
+func (w *webhookEventSink) deliver(data []byte) {
+	backoff := webhookBackoffBase
+	var lastErr error
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(data))
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 300 {
+				return
+			}
+			lastErr = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		} else {
+			lastErr = err
+		}
+
+		if attempt < webhookMaxAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	w.logger.Warnf("event webhook: giving up after %d attempts: %v", webhookMaxAttempts, lastErr)
+}
@@ -0,0 +1,82 @@
+package kettlecycletest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestWebhookEventSink(t *testing.T) {
+	t.Run("retries on failure and delivers once the endpoint recovers", func(t *testing.T) {
+		logger := logging.NewTestLogger(t)
+
+		var mu sync.Mutex
+		var attempts int
+		var lastBody map[string]interface{}
+		done := make(chan struct{})
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+
+			mu.Lock()
+			attempts++
+			n := attempts
+			lastBody = body
+			mu.Unlock()
+
+			if n < webhookMaxAttempts {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			close(done)
+		}))
+		defer srv.Close()
+
+		sink := newWebhookEventSink(srv.URL, logger)
+		sink.HandleEvent(map[string]interface{}{"type": "trial_started", "seq": float64(1)})
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected delivery to succeed within webhookMaxAttempts retries")
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if attempts != webhookMaxAttempts {
+			t.Errorf("expected %d attempts before success, got %d", webhookMaxAttempts, attempts)
+		}
+		if lastBody["type"] != "trial_started" {
+			t.Errorf("expected the delivered body to carry the event, got %v", lastBody)
+		}
+	})
+
+	t.Run("HandleEvent never blocks on delivery, even against a permanently down endpoint", func(t *testing.T) {
+		logger := logging.NewTestLogger(t)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		url := srv.URL
+		srv.Close() // every subsequent POST fails immediately with connection refused
+
+		sink := newWebhookEventSink(url, logger)
+
+		returned := make(chan struct{})
+		go func() {
+			sink.HandleEvent(map[string]interface{}{"type": "trial_started"})
+			close(returned)
+		}()
+
+		select {
+		case <-returned:
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("expected HandleEvent to return immediately instead of waiting on delivery retries -- a caller (or process exit) blocking on it would hang on a down endpoint")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package kettlecycletest
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.viam.com/rdk/logging"
+)
+
+// metricsRegistry is shared by every force-sensor instance in the module so
+// that multiple configured sensors are all scrapeable from one /metrics
+// endpoint instead of colliding on duplicate registration.
+var metricsRegistry = prometheus.NewRegistry()
+
+var (
+	forceCurrentForce = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kettle_force_sensor_current_force",
+		Help: "Most recent force reading.",
+	}, []string{"sensor"})
+
+	forceCaptureState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kettle_force_sensor_capture_state",
+		Help: "Current capture state (0=idle, 1=waiting, 2=capturing, 3=finalizing).",
+	}, []string{"sensor"})
+
+	forceCapturesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kettle_force_sensor_captures_total",
+		Help: "Captures started and ended, labeled by event and verdict.",
+	}, []string{"sensor", "event", "verdict"})
+
+	forcePeakHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kettle_force_sensor_peak_force",
+		Help:    "Distribution of peak force per capture.",
+		Buckets: prometheus.ExponentialBuckets(10, 2, 8),
+	}, []string{"sensor"})
+
+	forceDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kettle_force_sensor_capture_duration_seconds",
+		Help:    "Distribution of capture duration.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"sensor"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		forceCurrentForce,
+		forceCaptureState,
+		forceCapturesTotal,
+		forcePeakHistogram,
+		forceDurationHistogram,
+	)
+}
+
+// startMetricsServer starts an HTTP server exposing metricsRegistry on addr,
+// returning nil if addr is empty. Like startControllerMetricsServer, it is
+// owned by a single forceSensor instance and returned so Close can shut it
+// down gracefully -- unlike the sync.Once-guarded version this replaced,
+// every forceSensor instance gets its own listener, so a changed metrics_addr
+// on reconfigure is honored rather than silently ignored.
+func startMetricsServer(addr string, logger logging.Logger) *http.Server {
+	if addr == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("force-sensor metrics server on %s stopped: %v", addr, err)
+		}
+	}()
+	logger.Infof("force-sensor metrics server listening on %s", addr)
+
+	return srv
+}
@@ -2,8 +2,11 @@ package kettlecycletest
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.viam.com/rdk/components/sensor"
@@ -11,6 +14,12 @@ import (
 	"go.viam.com/rdk/resource"
 )
 
+// snapshotInterval bounds how often the sampling loop republishes a
+// forceSnapshot purely due to new samples arriving (state transitions are
+// always published immediately). This keeps Readings cheap for high sample
+// rates without making it lag noticeably behind the producer.
+const snapshotInterval = 10 * time.Millisecond
+
 var ForceSensor = resource.NewModel("viamdemo", "kettle-cycle-test", "force-sensor")
 
 func init() {
@@ -22,19 +31,45 @@ func init() {
 }
 
 type ForceSensorConfig struct {
-	LoadCell       string  `json:"load_cell"`                    // REQUIRED: name of load cell sensor
-	UseMockCurve   bool    `json:"use_mock_curve,omitempty"`     // optional: use mock force curve instead of hardware
-	ForceKey       string  `json:"force_key,omitempty"`
-	SampleRateHz   int     `json:"sample_rate_hz,omitempty"`
-	BufferSize     int     `json:"buffer_size,omitempty"`
-	ZeroThreshold  float64 `json:"zero_threshold,omitempty"`     // readings below this are "zero" (default: 5.0)
-	CaptureTimeout int     `json:"capture_timeout_ms,omitempty"` // timeout in ms (default: 10000)
+	LoadCell               string    `json:"load_cell"`                          // REQUIRED: name of load cell sensor
+	UseMockCurve           bool      `json:"use_mock_curve,omitempty"`           // optional: use mock force curve instead of hardware
+	ForceKey               string    `json:"force_key,omitempty"`
+	SampleRateHz           int       `json:"sample_rate_hz,omitempty"`
+	BufferSize             int       `json:"buffer_size,omitempty"`
+	ZeroThreshold          float64   `json:"zero_threshold,omitempty"`           // readings below this are "zero" (default: 5.0)
+	CaptureTimeout         int       `json:"capture_timeout_ms,omitempty"`       // timeout in ms (default: 10000)
+	RiseThresholds         []float64 `json:"rise_thresholds,omitempty"`          // [low_pct, high_pct] of peak for rise time (default: [10, 90])
+	DwellFraction          float64   `json:"dwell_fraction,omitempty"`           // fraction of peak counted as dwell (default: 0.9)
+	PeakProminence         float64   `json:"peak_prominence,omitempty"`          // min prominence (force units) for a local max to count toward peak_count (default: 0, counts every local max)
+	SmoothingWindowSamples int       `json:"smoothing_window_samples,omitempty"` // moving-average window applied before peak counting (default: 0, disabled)
+	AnalysisWindowMs       int       `json:"analysis_window_ms,omitempty"`       // if set, restrict computed stats to the trailing N ms of the capture (default: 0, whole capture)
+	ContactOnThreshold     float64   `json:"contact_on_threshold,omitempty"`     // optional: auto-end mode, force level that starts a capture
+	ContactOffThreshold    float64   `json:"contact_off_threshold,omitempty"`    // optional: auto-end mode, force level below which contact is considered lost
+	QuietWindowMs          int       `json:"quiet_window_ms,omitempty"`          // optional: auto-end mode, how long below contact_off_threshold before finalizing
+	TrialLogPath           string    `json:"trial_log_path,omitempty"`           // optional: append one JSONL record per trial to this file
+	TrialLogMaxBytes       int64     `json:"trial_log_max_bytes,omitempty"`      // rotate trial_log_path after it exceeds this size (default: 10MB)
+	TrialLogMaxFiles       int       `json:"trial_log_max_files,omitempty"`      // number of rotated trial_log_path files to retain (default: 5)
+
+	Acceptance *AcceptanceCriteria `json:"acceptance,omitempty"` // optional: default pass/fail criteria applied to every trial
+
+	MetricsAddr string `json:"metrics_addr,omitempty"` // optional: serve Prometheus metrics on this address (e.g. ":9101")
 }
 
 func (cfg *ForceSensorConfig) Validate(path string) ([]string, []string, error) {
 	if cfg.LoadCell == "" {
 		return nil, nil, fmt.Errorf("%s: load_cell is required", path)
 	}
+	if cfg.ContactOnThreshold > 0 || cfg.ContactOffThreshold > 0 {
+		zeroThreshold := cfg.ZeroThreshold
+		if zeroThreshold <= 0 {
+			zeroThreshold = 5.0
+		}
+		if !(cfg.ContactOffThreshold < zeroThreshold && zeroThreshold < cfg.ContactOnThreshold) {
+			return nil, nil, fmt.Errorf(
+				"%s: contact_off_threshold (%v) must be < zero_threshold (%v) < contact_on_threshold (%v)",
+				path, cfg.ContactOffThreshold, zeroThreshold, cfg.ContactOnThreshold)
+		}
+	}
 	return []string{cfg.LoadCell}, nil, nil
 }
 
@@ -120,11 +155,31 @@ func (r *sensorForceReader) ReadForce(ctx context.Context) (float64, error) {
 type captureState int
 
 const (
-	captureIdle captureState = iota
-	captureWaiting  // waiting for first non-zero reading
-	captureActive   // actively capturing samples
+	captureIdle       captureState = iota
+	captureWaiting                 // waiting for first non-zero reading
+	captureActive                  // actively capturing samples
+	captureFinalizing              // auto-end: quiet window elapsed, computing stats
 )
 
+// forceSnapshot is an immutable, point-in-time view of the fields Readings
+// needs. The sampling loop publishes a new one via forceSensor.snapshot
+// whenever the capture state changes, and at most every snapshotInterval
+// otherwise, so Readings can do a single atomic load instead of contending
+// with the sampling loop's mutex on every call.
+type forceSnapshot struct {
+	stateStr     string
+	trialID      string
+	cycleCount   int
+	shouldSync   bool
+	samples      []float64
+	hasMaxForce  bool
+	maxForce     float64
+	lastStats    map[string]interface{}
+	lastTrial    map[string]interface{}
+	lastVerdict  string
+	lastFailures []string
+}
+
 type forceSensor struct {
 	resource.AlwaysRebuild
 
@@ -136,15 +191,64 @@ type forceSensor struct {
 	bufferSize     int
 	zeroThreshold  float64
 	captureTimeout time.Duration
-
-	mu           sync.Mutex
-	samples      []float64
-	state        captureState
-	timeoutTimer *time.Timer
+	riseLowPct     float64
+	riseHighPct    float64
+	dwellFraction  float64
+
+	peakProminence   float64
+	smoothingWindow  int
+	analysisWindowMs int
+
+	// auto-end mode: zero values disable it and preserve explicit end_capture-only behavior
+	contactOnThreshold  float64
+	contactOffThreshold float64
+	quietWindow         time.Duration
+
+	// trialLog persists one JSONL record per finished trial; nil if unconfigured.
+	trialLog *trialLogger
+
+	// acceptance is the default criteria applied to every trial unless
+	// overridden per-trial via start_capture's "acceptance" field.
+	acceptance *AcceptanceCriteria
+
+	// mu serializes the state transitions triggered by handleStartCapture,
+	// handleEndCapture, and the sampling loop; it is never held by Readings.
+	mu             sync.Mutex
+	samples        []float64
+	sampleTimesNs  []int64
+	state          captureState
+	timeoutTimer   *time.Timer
+	lastAboveOffNs int64
+	lastSnapshotAt time.Time
 
 	// Trial metadata passed via start_capture
-	trialID    string
-	cycleCount int
+	trialID          string
+	cycleCount       int
+	captureStarted   time.Time
+	activeAcceptance *AcceptanceCriteria
+
+	lastVerdict  string
+	lastFailures []string
+
+	// subscriptions backs the subscribe/unsubscribe/poll commands used for
+	// live-streaming samples without paying the cost of copying the whole
+	// buffer on every Readings call.
+	subscriptions map[string]*forceSubscription
+	nextSubID     int
+
+	// lastStats holds the statistics bundle from the most recently completed
+	// capture, surfaced in Readings until the next start_capture.
+	lastStats map[string]interface{}
+
+	// lastTrial holds the full result of the most recently finalized trial so a
+	// poller (e.g. cycle-sensor) can pick it up without racing an explicit end_capture.
+	lastTrial map[string]interface{}
+
+	// snapshot is the published view Readings reads from; see forceSnapshot.
+	snapshot atomic.Pointer[forceSnapshot]
+
+	// metricsServer serves metricsRegistry on conf.MetricsAddr; nil if unconfigured.
+	metricsServer *http.Server
 }
 
 func newForceSensor(ctx context.Context, deps resource.Dependencies, rawConf resource.Config, logger logging.Logger) (sensor.Sensor, error) {
@@ -173,6 +277,26 @@ func newForceSensor(ctx context.Context, deps resource.Dependencies, rawConf res
 		captureTimeout = 10000 // 10 seconds default
 	}
 
+	riseLowPct, riseHighPct := 10.0, 90.0
+	if len(conf.RiseThresholds) == 2 {
+		riseLowPct, riseHighPct = conf.RiseThresholds[0], conf.RiseThresholds[1]
+	}
+
+	dwellFraction := conf.DwellFraction
+	if dwellFraction <= 0 {
+		dwellFraction = 0.9
+	}
+
+	quietWindow := time.Duration(conf.QuietWindowMs) * time.Millisecond
+
+	var trialLog *trialLogger
+	if conf.TrialLogPath != "" {
+		trialLog, err = newTrialLogger(conf.TrialLogPath, conf.TrialLogMaxBytes, conf.TrialLogMaxFiles, logger)
+		if err != nil {
+			return nil, fmt.Errorf("opening trial_log_path: %w", err)
+		}
+	}
+
 	var reader forceReader
 	if conf.UseMockCurve {
 		reader = newMockForceReader()
@@ -187,60 +311,65 @@ func newForceSensor(ctx context.Context, deps resource.Dependencies, rawConf res
 	}
 
 	fs := &forceSensor{
-		name:           rawConf.ResourceName(),
-		logger:         logger,
-		reader:         reader,
-		sampleRateHz:   sampleRate,
-		bufferSize:     bufferSize,
-		zeroThreshold:  zeroThreshold,
-		captureTimeout: time.Duration(captureTimeout) * time.Millisecond,
-		samples:        make([]float64, 0, bufferSize),
-		state:          captureIdle,
-	}
+		name:                rawConf.ResourceName(),
+		logger:              logger,
+		reader:              reader,
+		sampleRateHz:        sampleRate,
+		bufferSize:          bufferSize,
+		zeroThreshold:       zeroThreshold,
+		captureTimeout:      time.Duration(captureTimeout) * time.Millisecond,
+		riseLowPct:          riseLowPct,
+		riseHighPct:         riseHighPct,
+		dwellFraction:       dwellFraction,
+		peakProminence:      conf.PeakProminence,
+		smoothingWindow:     conf.SmoothingWindowSamples,
+		analysisWindowMs:    conf.AnalysisWindowMs,
+		contactOnThreshold:  conf.ContactOnThreshold,
+		contactOffThreshold: conf.ContactOffThreshold,
+		quietWindow:         quietWindow,
+		trialLog:            trialLog,
+		acceptance:          conf.Acceptance,
+		samples:             make([]float64, 0, bufferSize),
+		sampleTimesNs:       make([]int64, 0, bufferSize),
+		state:               captureIdle,
+	}
+
+	fs.publishSnapshotLocked()
+
+	fs.metricsServer = startMetricsServer(conf.MetricsAddr, logger)
 
 	go fs.samplingLoop()
 
 	return fs, nil
 }
 
-func (fs *forceSensor) Name() resource.Name {
-	return fs.name
-}
-
-func (fs *forceSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
-	fs.mu.Lock()
-	samplesCopy := make([]float64, len(fs.samples))
-	copy(samplesCopy, fs.samples)
-	state := fs.state
-	trialID := fs.trialID
-	cycleCount := fs.cycleCount
-	fs.mu.Unlock()
-
-	samplesInterface := make([]interface{}, len(samplesCopy))
-	for i, v := range samplesCopy {
-		samplesInterface[i] = v
-	}
-
+// publishSnapshotLocked builds a forceSnapshot from the current state and
+// publishes it for Readings to read lock-free. Callers must hold fs.mu.
+func (fs *forceSensor) publishSnapshotLocked() {
 	stateStr := "idle"
-	switch state {
+	switch fs.state {
 	case captureWaiting:
 		stateStr = "waiting"
 	case captureActive:
 		stateStr = "capturing"
+	case captureFinalizing:
+		stateStr = "finalizing"
 	}
 
-	// should_sync is true when we have an active trial (trialID is set)
-	shouldSync := trialID != ""
+	samplesCopy := make([]float64, len(fs.samples))
+	copy(samplesCopy, fs.samples)
 
-	result := map[string]interface{}{
-		"trial_id":      trialID,
-		"cycle_count":   cycleCount,
-		"should_sync":   shouldSync,
-		"samples":       samplesInterface,
-		"sample_count":  len(samplesCopy),
-		"capture_state": stateStr,
+	snap := &forceSnapshot{
+		stateStr:     stateStr,
+		trialID:      fs.trialID,
+		cycleCount:   fs.cycleCount,
+		shouldSync:   fs.trialID != "",
+		samples:      samplesCopy,
+		lastStats:    fs.lastStats,
+		lastTrial:    fs.lastTrial,
+		lastVerdict:  fs.lastVerdict,
+		lastFailures: fs.lastFailures,
 	}
-
 	if len(samplesCopy) > 0 {
 		max := samplesCopy[0]
 		for _, v := range samplesCopy[1:] {
@@ -248,7 +377,61 @@ func (fs *forceSensor) Readings(ctx context.Context, extra map[string]interface{
 				max = v
 			}
 		}
-		result["max_force"] = max
+		snap.hasMaxForce = true
+		snap.maxForce = max
+	}
+
+	fs.snapshot.Store(snap)
+	fs.lastSnapshotAt = time.Now()
+}
+
+func (fs *forceSensor) Name() resource.Name {
+	return fs.name
+}
+
+// Readings never takes fs.mu: it reads a single atomically-published
+// forceSnapshot, so it never contends with the sampling loop's producer lock.
+func (fs *forceSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	snap := fs.snapshot.Load()
+	if snap == nil {
+		return map[string]interface{}{
+			"trial_id":      "",
+			"cycle_count":   0,
+			"should_sync":   false,
+			"samples":       []interface{}{},
+			"sample_count":  0,
+			"capture_state": "idle",
+		}, nil
+	}
+
+	samplesInterface := make([]interface{}, len(snap.samples))
+	for i, v := range snap.samples {
+		samplesInterface[i] = v
+	}
+
+	result := map[string]interface{}{
+		"trial_id":      snap.trialID,
+		"cycle_count":   snap.cycleCount,
+		"should_sync":   snap.shouldSync,
+		"samples":       samplesInterface,
+		"sample_count":  len(snap.samples),
+		"capture_state": snap.stateStr,
+	}
+
+	if snap.hasMaxForce {
+		result["max_force"] = snap.maxForce
+	}
+
+	for k, v := range snap.lastStats {
+		result[k] = v
+	}
+
+	if snap.lastTrial != nil {
+		result["last_trial"] = snap.lastTrial
+	}
+	if snap.lastVerdict != "" {
+		result["last_verdict"] = snap.lastVerdict
+		result["last_failures"] = snap.lastFailures
 	}
 
 	return result, nil
@@ -275,19 +458,62 @@ func (fs *forceSensor) samplingLoop() {
 				continue
 			}
 
+			now := time.Now()
+
+			onThresh := fs.zeroThreshold
+			if fs.contactOnThreshold > 0 {
+				onThresh = fs.contactOnThreshold
+			}
+			offThresh := fs.zeroThreshold
+			if fs.contactOffThreshold > 0 {
+				offThresh = fs.contactOffThreshold
+			}
+
 			fs.mu.Lock()
-			if fs.state == captureWaiting && force >= fs.zeroThreshold {
+			for _, sub := range fs.subscriptions {
+				if sub.push(forceSamplePoint{TimestampNs: now.UnixNano(), Force: force}) {
+					forceSamplesDropped.WithLabelValues(fs.name.Name).Inc()
+				}
+			}
+			forceCurrentForce.WithLabelValues(fs.name.Name).Set(force)
+			forceCaptureState.WithLabelValues(fs.name.Name).Set(float64(fs.state))
+
+			stateChanged := false
+			if fs.state == captureWaiting && force >= onThresh {
 				// First non-zero reading - start capturing
 				fs.state = captureActive
 				fs.samples = fs.samples[:0]
+				fs.sampleTimesNs = fs.sampleTimesNs[:0]
+				fs.lastAboveOffNs = now.UnixNano()
 				fs.logger.Infof("force capture started (first reading: %.2f)", force)
+				stateChanged = true
 			}
 
+			finalized := false
 			if fs.state == captureActive {
 				if len(fs.samples) >= fs.bufferSize {
 					fs.samples = fs.samples[1:]
+					fs.sampleTimesNs = fs.sampleTimesNs[1:]
 				}
 				fs.samples = append(fs.samples, force)
+				fs.sampleTimesNs = append(fs.sampleTimesNs, now.UnixNano())
+
+				if force >= offThresh {
+					fs.lastAboveOffNs = now.UnixNano()
+				}
+
+				if fs.quietWindow > 0 && time.Duration(now.UnixNano()-fs.lastAboveOffNs) >= fs.quietWindow {
+					fs.logger.Infof("auto-ending capture: quiet for %v", fs.quietWindow)
+					fs.finalizeLocked() // publishes its own snapshot
+					finalized = true
+				}
+			}
+
+			// State transitions publish immediately; plain sample appends are
+			// throttled to snapshotInterval so high sample rates don't pay a
+			// copy-and-publish cost on every tick.
+			if !finalized && (stateChanged || now.Sub(fs.lastSnapshotAt) >= snapshotInterval) {
+				fs.publishSnapshotLocked()
 			}
 			fs.mu.Unlock()
 		}
@@ -305,6 +531,14 @@ func (fs *forceSensor) DoCommand(ctx context.Context, cmd map[string]interface{}
 		return fs.handleStartCapture(cmd)
 	case "end_capture":
 		return fs.handleEndCapture()
+	case "get_acceptance":
+		return fs.handleGetAcceptance()
+	case "subscribe":
+		return fs.handleSubscribe()
+	case "unsubscribe":
+		return fs.handleUnsubscribe(cmd)
+	case "poll":
+		return fs.handlePoll(cmd)
 	default:
 		return nil, fmt.Errorf("unknown command: %s", command)
 	}
@@ -331,8 +565,21 @@ func (fs *forceSensor) handleStartCapture(cmd map[string]interface{}) (map[strin
 		fs.cycleCount = cycleCount
 	}
 
+	fs.activeAcceptance = fs.acceptance
+	if raw, ok := cmd["acceptance"]; ok {
+		ac, err := parseAcceptanceOverride(raw)
+		if err != nil {
+			fs.logger.Warnf("ignoring invalid acceptance override: %v", err)
+		} else {
+			fs.activeAcceptance = ac
+		}
+	}
+
+	fs.lastStats = nil
 	fs.state = captureWaiting
+	fs.captureStarted = time.Now()
 	fs.samples = fs.samples[:0]
+	fs.sampleTimesNs = fs.sampleTimesNs[:0]
 
 	// Start timeout timer
 	fs.timeoutTimer = time.AfterFunc(fs.captureTimeout, func() {
@@ -340,7 +587,10 @@ func (fs *forceSensor) handleStartCapture(cmd map[string]interface{}) (map[strin
 		defer fs.mu.Unlock()
 		if fs.state != captureIdle {
 			fs.logger.Errorf("capture timeout: end_capture not called within %v", fs.captureTimeout)
-			fs.state = captureIdle
+			// finalizeLocked republishes the snapshot as idle, matching the
+			// explicit end_capture path; without it Readings() keeps
+			// reporting the stale capturing state forever after a timeout.
+			fs.finalizeLocked()
 		}
 	})
 
@@ -349,6 +599,9 @@ func (fs *forceSensor) handleStartCapture(cmd map[string]interface{}) (map[strin
 		mock.SetContact(true)
 	}
 
+	forceCapturesTotal.WithLabelValues(fs.name.Name, "started", "").Inc()
+	fs.publishSnapshotLocked()
+
 	fs.logger.Infof("capture started, waiting for non-zero reading (threshold: %.2f)", fs.zeroThreshold)
 	return map[string]interface{}{"status": "waiting"}, nil
 }
@@ -361,6 +614,17 @@ func (fs *forceSensor) handleEndCapture() (map[string]interface{}, error) {
 		return nil, fmt.Errorf("no capture in progress")
 	}
 
+	return fs.finalizeLocked(), nil
+}
+
+// finalizeLocked computes the final stats bundle for the in-progress capture,
+// resets state to idle, and stashes the result as lastTrial. Callers must
+// hold fs.mu. Shared by the explicit end_capture path and the auto-end path
+// in samplingLoop so both produce identical trial results.
+func (fs *forceSensor) finalizeLocked() map[string]interface{} {
+	prevState := fs.state
+	fs.state = captureFinalizing
+
 	// Cancel timeout
 	if fs.timeoutTimer != nil {
 		fs.timeoutTimer.Stop()
@@ -373,45 +637,127 @@ func (fs *forceSensor) handleEndCapture() (map[string]interface{}, error) {
 	}
 
 	sampleCount := len(fs.samples)
-	var maxForce float64
-	if sampleCount > 0 {
-		maxForce = fs.samples[0]
-		for _, v := range fs.samples[1:] {
-			if v > maxForce {
-				maxForce = v
-			}
-		}
-	}
+	stats := computeForceStats(fs.samples, fs.sampleTimesNs, statsOptions{
+		riseLowPct:       fs.riseLowPct,
+		riseHighPct:      fs.riseHighPct,
+		dwellFraction:    fs.dwellFraction,
+		peakProminence:   fs.peakProminence,
+		smoothingWindow:  fs.smoothingWindow,
+		analysisWindowMs: fs.analysisWindowMs,
+		sampleRateHz:     fs.sampleRateHz,
+		zeroThreshold:    fs.zeroThreshold,
+	})
+	statsMap := stats.asMap()
+	fs.lastStats = statsMap
 
-	prevState := fs.state
-	fs.state = captureIdle
+	verdict, failures := fs.activeAcceptance.evaluate(stats)
+	fs.lastVerdict = verdict
+	fs.lastFailures = failures
 
 	// Clear trial metadata so should_sync becomes false
 	trialID := fs.trialID
 	cycleCount := fs.cycleCount
 	fs.trialID = ""
 	fs.cycleCount = 0
+	fs.activeAcceptance = nil
 
 	stateStr := "waiting"
 	if prevState == captureActive {
 		stateStr = "capturing"
 	}
+	fs.logger.Infof("capture ended (was %s): %d samples, max force: %.2f", stateStr, sampleCount, stats.max)
 
-	fs.logger.Infof("capture ended (was %s): %d samples, max force: %.2f", stateStr, sampleCount, maxForce)
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"status":       "completed",
 		"sample_count": sampleCount,
-		"max_force":    maxForce,
 		"trial_id":     trialID,
 		"cycle_count":  cycleCount,
-	}, nil
+		"verdict":      verdict,
+		"failures":     failures,
+	}
+	for k, v := range statsMap {
+		result[k] = v
+	}
+
+	fs.lastTrial = result
+	fs.state = captureIdle
+
+	forceCapturesTotal.WithLabelValues(fs.name.Name, "ended", verdict).Inc()
+	forcePeakHistogram.WithLabelValues(fs.name.Name).Observe(stats.max)
+	forceDurationHistogram.WithLabelValues(fs.name.Name).Observe(stats.captureDurationMs / 1000)
+
+	if fs.trialLog != nil {
+		fs.trialLog.WriteTrial(fs.trialLogRecordLocked(trialID, cycleCount, statsMap))
+	}
+
+	fs.publishSnapshotLocked()
+
+	return result
+}
+
+// trialLogRecordLocked builds the full record persisted to trial_log_path.
+// Callers must hold fs.mu.
+func (fs *forceSensor) trialLogRecordLocked(trialID string, cycleCount int, statsMap map[string]interface{}) map[string]interface{} {
+	samples := make([]map[string]interface{}, len(fs.samples))
+	for i, v := range fs.samples {
+		samples[i] = map[string]interface{}{
+			"timestamp_ns": fs.sampleTimesNs[i],
+			"force":        v,
+		}
+	}
+
+	record := map[string]interface{}{
+		"trial_id":       trialID,
+		"cycle_count":    cycleCount,
+		"start_time":     fs.captureStarted.Format(time.RFC3339Nano),
+		"end_time":       time.Now().Format(time.RFC3339Nano),
+		"sample_rate_hz": fs.sampleRateHz,
+		"samples":        samples,
+	}
+	for k, v := range statsMap {
+		record[k] = v
+	}
+	return record
 }
 
-func (fs *forceSensor) Close(context.Context) error {
+func (fs *forceSensor) handleGetAcceptance() (map[string]interface{}, error) {
+	fs.mu.Lock()
+	acceptance := fs.acceptance
+	fs.mu.Unlock()
+
+	if acceptance == nil {
+		return map[string]interface{}{"acceptance": nil}, nil
+	}
+
+	data, err := json.Marshal(acceptance)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling acceptance criteria: %w", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("unmarshaling acceptance criteria: %w", err)
+	}
+	return map[string]interface{}{"acceptance": m}, nil
+}
+
+func (fs *forceSensor) Close(ctx context.Context) error {
 	fs.mu.Lock()
 	if fs.timeoutTimer != nil {
 		fs.timeoutTimer.Stop()
 	}
 	fs.mu.Unlock()
+
+	if fs.metricsServer != nil {
+		if err := fs.metricsServer.Shutdown(ctx); err != nil {
+			fs.logger.Warnf("metrics server shutdown: %v", err)
+		}
+	}
+
+	forceCurrentForce.DeleteLabelValues(fs.name.Name)
+	forceCaptureState.DeleteLabelValues(fs.name.Name)
+
+	if fs.trialLog != nil {
+		return fs.trialLog.Close()
+	}
 	return nil
 }
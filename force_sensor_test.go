@@ -50,7 +50,7 @@ func TestForceSensorConfig(t *testing.T) {
 
 // newTestForceSensor creates a force sensor with mock reader for testing
 func newTestForceSensor(t *testing.T) *forceSensor {
-	return &forceSensor{
+	fs := &forceSensor{
 		name:           resource.NewName(resource.APINamespaceRDK.WithComponentType("sensor"), "test"),
 		logger:         logging.NewTestLogger(t),
 		reader:         newMockForceReader(),
@@ -58,9 +58,15 @@ func newTestForceSensor(t *testing.T) *forceSensor {
 		bufferSize:     100,
 		zeroThreshold:  5.0,
 		captureTimeout: 10 * time.Second,
+		riseLowPct:     10,
+		riseHighPct:    90,
+		dwellFraction:  0.9,
 		samples:        make([]float64, 0, 100),
+		sampleTimesNs:  make([]int64, 0, 100),
 		state:          captureIdle,
 	}
+	fs.publishSnapshotLocked()
+	return fs
 }
 
 func TestForceSensor_StateMachine(t *testing.T) {
@@ -155,6 +161,32 @@ func TestForceSensor_StateMachine(t *testing.T) {
 			t.Error("expected error when ending capture that wasn't started")
 		}
 	})
+
+	t.Run("capture timeout finalizes and republishes idle state", func(t *testing.T) {
+		fs := newTestForceSensor(t)
+		fs.captureTimeout = 20 * time.Millisecond
+		go fs.samplingLoop()
+
+		_, err := fs.handleStartCapture(map[string]interface{}{})
+		if err != nil {
+			t.Fatalf("handleStartCapture failed: %v", err)
+		}
+
+		// Never call end_capture; let captureTimeout fire instead.
+		time.Sleep(100 * time.Millisecond)
+
+		readings, _ := fs.Readings(context.Background(), nil)
+		if readings["capture_state"] != "idle" {
+			t.Errorf("expected capture_state=idle after a capture timeout, got %v", readings["capture_state"])
+		}
+
+		// A subsequent start_capture must be accepted, proving the state
+		// machine really recovered rather than just Readings() masking it.
+		if _, err := fs.handleStartCapture(map[string]interface{}{}); err != nil {
+			t.Errorf("expected start_capture to succeed after a timed-out capture recovered, got: %v", err)
+		}
+		fs.handleEndCapture()
+	})
 }
 
 func TestForceSensor_ShouldSync(t *testing.T) {
@@ -218,7 +250,11 @@ func TestForceSensor_Buffer(t *testing.T) {
 			bufferSize:     bufferSize,
 			zeroThreshold:  5.0,
 			captureTimeout: 10 * time.Second,
+			riseLowPct:     10,
+			riseHighPct:    90,
+			dwellFraction:  0.9,
 			samples:        make([]float64, 0, bufferSize),
+			sampleTimesNs:  make([]int64, 0, bufferSize),
 			state:          captureIdle,
 		}
 
@@ -241,8 +277,10 @@ func TestForceSensor_Buffer(t *testing.T) {
 func TestForceSensor_MaxForce(t *testing.T) {
 	t.Run("correctly identifies max from samples", func(t *testing.T) {
 		fs := newTestForceSensor(t)
-		// Inject known samples directly
+		// Inject known samples directly and republish, since Readings now
+		// reads from the published snapshot rather than fs.samples directly.
 		fs.samples = []float64{10.0, 50.0, 30.0, 25.0}
+		fs.publishSnapshotLocked()
 
 		readings, _ := fs.Readings(context.Background(), nil)
 		maxForce, ok := readings["max_force"].(float64)
@@ -255,6 +293,188 @@ func TestForceSensor_MaxForce(t *testing.T) {
 	})
 }
 
+func TestForceSensor_AutoEnd(t *testing.T) {
+	t.Run("quiet window auto-finalizes capture and stashes last_trial", func(t *testing.T) {
+		fs := newTestForceSensor(t)
+		fs.contactOnThreshold = 10
+		fs.contactOffThreshold = 2
+		fs.quietWindow = 30 * time.Millisecond
+		go fs.samplingLoop()
+
+		fs.handleStartCapture(map[string]interface{}{"trial_id": "trial-auto"})
+
+		// Mock reader ramps 50->200 then holds; wait for capture to go active,
+		// then drop contact so the reader returns near-zero and the quiet
+		// window elapses.
+		time.Sleep(50 * time.Millisecond)
+		if mock, ok := fs.reader.(*mockForceReader); ok {
+			mock.SetContact(false)
+		}
+		time.Sleep(100 * time.Millisecond)
+
+		readings, _ := fs.Readings(context.Background(), nil)
+		if readings["capture_state"] != "idle" {
+			t.Errorf("expected capture_state=idle after auto-end, got %v", readings["capture_state"])
+		}
+
+		lastTrial, ok := readings["last_trial"].(map[string]interface{})
+		if !ok {
+			t.Fatal("expected last_trial in readings after auto-end")
+		}
+		if lastTrial["trial_id"] != "trial-auto" {
+			t.Errorf("expected last_trial trial_id=trial-auto, got %v", lastTrial["trial_id"])
+		}
+	})
+
+	t.Run("explicit end_capture still works with auto-end configured", func(t *testing.T) {
+		fs := newTestForceSensor(t)
+		fs.contactOnThreshold = 10
+		fs.contactOffThreshold = 2
+		fs.quietWindow = 5 * time.Second // long enough not to fire during the test
+		go fs.samplingLoop()
+
+		fs.handleStartCapture(map[string]interface{}{})
+		time.Sleep(50 * time.Millisecond)
+
+		result, err := fs.handleEndCapture()
+		if err != nil {
+			t.Fatalf("handleEndCapture failed: %v", err)
+		}
+		if result["status"] != "completed" {
+			t.Errorf("expected status=completed, got %v", result["status"])
+		}
+	})
+}
+
+func TestForceSensor_Acceptance(t *testing.T) {
+	t.Run("handleGetAcceptance returns configured criteria", func(t *testing.T) {
+		fs := newTestForceSensor(t)
+		fs.acceptance = &AcceptanceCriteria{MinPeakForce: 150}
+
+		result, err := fs.handleGetAcceptance()
+		if err != nil {
+			t.Fatalf("handleGetAcceptance failed: %v", err)
+		}
+		acceptance, ok := result["acceptance"].(map[string]interface{})
+		if !ok {
+			t.Fatal("expected acceptance map in result")
+		}
+		if acceptance["min_peak_force"] != 150.0 {
+			t.Errorf("expected min_peak_force=150, got %v", acceptance["min_peak_force"])
+		}
+	})
+
+	t.Run("end_capture sets verdict and failures", func(t *testing.T) {
+		fs := newTestForceSensor(t)
+		fs.acceptance = &AcceptanceCriteria{MinPeakForce: 1000} // unreachable by the mock curve
+		go fs.samplingLoop()
+
+		fs.handleStartCapture(map[string]interface{}{})
+		time.Sleep(50 * time.Millisecond)
+
+		result, err := fs.handleEndCapture()
+		if err != nil {
+			t.Fatalf("handleEndCapture failed: %v", err)
+		}
+		if result["verdict"] != "fail" {
+			t.Errorf("expected verdict=fail, got %v", result["verdict"])
+		}
+		if failures, ok := result["failures"].([]string); !ok || len(failures) == 0 {
+			t.Errorf("expected non-empty failures, got %v", result["failures"])
+		}
+
+		readings, _ := fs.Readings(context.Background(), nil)
+		if readings["last_verdict"] != "fail" {
+			t.Errorf("expected last_verdict=fail, got %v", readings["last_verdict"])
+		}
+	})
+
+	t.Run("start_capture accepts a per-trial acceptance override", func(t *testing.T) {
+		fs := newTestForceSensor(t)
+		fs.acceptance = nil
+		go fs.samplingLoop()
+
+		fs.handleStartCapture(map[string]interface{}{
+			"acceptance": map[string]interface{}{"min_peak_force": 1000.0},
+		})
+		time.Sleep(50 * time.Millisecond)
+
+		result, _ := fs.handleEndCapture()
+		if result["verdict"] != "fail" {
+			t.Errorf("expected per-trial override to fail acceptance, got %v", result["verdict"])
+		}
+	})
+}
+
+func TestForceSensor_Subscriptions(t *testing.T) {
+	t.Run("poll drains samples collected since the last poll", func(t *testing.T) {
+		fs := newTestForceSensor(t)
+		go fs.samplingLoop()
+
+		subResult, err := fs.handleSubscribe()
+		if err != nil {
+			t.Fatalf("handleSubscribe failed: %v", err)
+		}
+		subID, ok := subResult["subscription_id"].(string)
+		if !ok || subID == "" {
+			t.Fatalf("expected subscription_id, got %v", subResult)
+		}
+
+		fs.handleStartCapture(map[string]interface{}{})
+		time.Sleep(50 * time.Millisecond)
+
+		pollResult, err := fs.handlePoll(map[string]interface{}{"subscription_id": subID})
+		if err != nil {
+			t.Fatalf("handlePoll failed: %v", err)
+		}
+		if pollResult["count"].(int) == 0 {
+			t.Error("expected at least one sample since subscribe")
+		}
+
+		fs.handleEndCapture()
+	})
+
+	t.Run("unsubscribe removes the subscription", func(t *testing.T) {
+		fs := newTestForceSensor(t)
+
+		subResult, _ := fs.handleSubscribe()
+		subID := subResult["subscription_id"].(string)
+
+		_, err := fs.handleUnsubscribe(map[string]interface{}{"subscription_id": subID})
+		if err != nil {
+			t.Fatalf("handleUnsubscribe failed: %v", err)
+		}
+
+		_, err = fs.handlePoll(map[string]interface{}{"subscription_id": subID})
+		if err == nil {
+			t.Error("expected error polling an unsubscribed subscription_id")
+		}
+	})
+
+	t.Run("poll with unknown subscription_id errors", func(t *testing.T) {
+		fs := newTestForceSensor(t)
+		_, err := fs.handlePoll(map[string]interface{}{"subscription_id": "nonexistent"})
+		if err == nil {
+			t.Error("expected error for unknown subscription_id")
+		}
+	})
+
+	t.Run("push reports a drop once the buffer is full", func(t *testing.T) {
+		sub := &forceSubscription{}
+		for i := 0; i < subscriptionBufferCap; i++ {
+			if dropped := sub.push(forceSamplePoint{TimestampNs: int64(i)}); dropped {
+				t.Fatalf("unexpected drop filling sample %d", i)
+			}
+		}
+		if dropped := sub.push(forceSamplePoint{TimestampNs: subscriptionBufferCap}); !dropped {
+			t.Error("expected push past capacity to report a drop")
+		}
+		if len(sub.samples) != subscriptionBufferCap {
+			t.Errorf("expected buffer to stay capped at %d, got %d", subscriptionBufferCap, len(sub.samples))
+		}
+	})
+}
+
 func TestForceSensor_ThreadSafety(t *testing.T) {
 	t.Run("concurrent reads during active sampling", func(t *testing.T) {
 		fs := newTestForceSensor(t)
@@ -280,4 +500,84 @@ func TestForceSensor_ThreadSafety(t *testing.T) {
 		wg.Wait()
 		fs.handleEndCapture()
 	})
+
+	t.Run("no torn reads of metadata at 1kHz sampling", func(t *testing.T) {
+		fs := newTestForceSensor(t)
+		fs.sampleRateHz = 1000
+		go fs.samplingLoop()
+
+		fs.handleStartCapture(map[string]interface{}{
+			"trial_id":    "trial-torn-read",
+			"cycle_count": 7,
+		})
+
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					readings, err := fs.Readings(context.Background(), nil)
+					if err != nil {
+						t.Errorf("concurrent Readings failed: %v", err)
+						return
+					}
+					// trial_id, cycle_count, and should_sync all come from the
+					// same published snapshot, so while a trial is active they
+					// must always agree -- any mismatch would mean Readings
+					// observed a torn mix of old and new state.
+					if readings["should_sync"] == true && readings["trial_id"] != "trial-torn-read" {
+						t.Errorf("torn read: should_sync=true but trial_id=%v", readings["trial_id"])
+						return
+					}
+				}
+			}()
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		close(stop)
+		wg.Wait()
+		fs.handleEndCapture()
+	})
+}
+
+// BenchmarkReadings_ConcurrentSampling measures Readings throughput while the
+// sampling loop is actively producing at 1kHz, demonstrating that the
+// snapshot-based lock-free read path stays cheap under producer contention.
+func BenchmarkReadings_ConcurrentSampling(b *testing.B) {
+	fs := &forceSensor{
+		name:           resource.NewName(resource.APINamespaceRDK.WithComponentType("sensor"), "bench"),
+		logger:         logging.NewTestLogger(b),
+		reader:         newMockForceReader(),
+		sampleRateHz:   1000,
+		bufferSize:     100,
+		zeroThreshold:  5.0,
+		captureTimeout: 10 * time.Second,
+		riseLowPct:     10,
+		riseHighPct:    90,
+		dwellFraction:  0.9,
+		samples:        make([]float64, 0, 100),
+		sampleTimesNs:  make([]int64, 0, 100),
+		state:          captureIdle,
+	}
+	fs.publishSnapshotLocked()
+	go fs.samplingLoop()
+
+	fs.handleStartCapture(map[string]interface{}{})
+	defer fs.handleEndCapture()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := fs.Readings(context.Background(), nil); err != nil {
+				b.Fatalf("Readings failed: %v", err)
+			}
+		}
+	})
 }
@@ -0,0 +1,305 @@
+package kettlecycletest
+
+import "math"
+
+// forceStats is the full statistics bundle computed over a completed capture.
+type forceStats struct {
+	sampleCount             int
+	min                     float64
+	max                     float64
+	mean                    float64
+	stddev                  float64
+	rms                     float64
+	peakToPeak              float64
+	captureDurationMs       float64
+	timeToPeakMs            float64
+	riseTimeMs              float64
+	dwellMs                 float64
+	impulse                 float64
+	impulseNs               float64
+	contactDurationMs       float64
+	samplesBelowDuringDwell int
+	peakCount               int
+}
+
+func (s forceStats) asMap() map[string]interface{} {
+	return map[string]interface{}{
+		"sample_count":                         s.sampleCount,
+		"min_force":                            s.min,
+		"max_force":                            s.max,
+		"mean_force":                           s.mean,
+		"stddev_force":                         s.stddev,
+		"rms_force":                            s.rms,
+		"peak_to_peak_force":                   s.peakToPeak,
+		"capture_duration_ms":                  s.captureDurationMs,
+		"time_to_peak_ms":                      s.timeToPeakMs,
+		"rise_time_ms":                         s.riseTimeMs,
+		"dwell_time_ms":                        s.dwellMs,
+		"impulse":                              s.impulse,
+		"impulse_ns":                           s.impulseNs,
+		"contact_duration_ms":                  s.contactDurationMs,
+		"samples_below_threshold_during_dwell": s.samplesBelowDuringDwell,
+		"peak_count":                           s.peakCount,
+	}
+}
+
+// statsOptions bundles computeForceStats' tunables so adding another knob
+// doesn't grow its positional argument list further.
+type statsOptions struct {
+	riseLowPct       float64
+	riseHighPct      float64
+	dwellFraction    float64
+	peakProminence   float64 // minimum prominence (in force units) for a local max to count toward peakCount; 0 counts every local max
+	smoothingWindow  int     // moving-average window (in samples) applied before peak counting; 0 disables smoothing
+	analysisWindowMs int     // if > 0, restrict every computed stat to the last N ms of the capture
+	sampleRateHz     int     // nominal sample rate; impulseNs/contactDurationMs use 1/sampleRateHz as a fixed dt instead of real inter-sample timestamps, so 0 disables them
+	zeroThreshold    float64 // contactDurationMs counts samples at or above this
+}
+
+// computeForceStats derives the statistics bundle from a timestamped sample
+// series. Returns the zero value if samples is empty.
+func computeForceStats(samples []float64, timestampsNs []int64, opts statsOptions) forceStats {
+	samples, timestampsNs = windowSamples(samples, timestampsNs, opts.analysisWindowMs)
+
+	var stats forceStats
+	stats.sampleCount = len(samples)
+	if len(samples) == 0 {
+		return stats
+	}
+
+	stats.min = samples[0]
+	stats.max = samples[0]
+	var sum, sumSquares, mean, m2 float64
+	peakIdx := 0
+	for i, v := range samples {
+		if v < stats.min {
+			stats.min = v
+		}
+		if v > stats.max {
+			stats.max = v
+			peakIdx = i
+		}
+		sum += v
+		sumSquares += v * v
+
+		// Welford's online algorithm: tracking the running mean and sum of
+		// squared differences from it avoids the cancellation the naive
+		// sum(x^2)/n - mean^2 formula suffers when samples cluster tightly
+		// around a non-round value, which otherwise can drive the bracketed
+		// term slightly negative and make math.Sqrt return NaN.
+		delta := v - mean
+		mean += delta / float64(i+1)
+		m2 += delta * (v - mean)
+	}
+	stats.mean = sum / float64(len(samples))
+	stats.rms = math.Sqrt(sumSquares / float64(len(samples)))
+	stats.stddev = math.Sqrt(m2 / float64(len(samples)))
+	stats.peakToPeak = stats.max - stats.min
+
+	if len(timestampsNs) == len(samples) && len(samples) > 1 {
+		startNs := timestampsNs[0]
+		endNs := timestampsNs[len(timestampsNs)-1]
+		stats.captureDurationMs = float64(endNs-startNs) / 1e6
+		stats.timeToPeakMs = float64(timestampsNs[peakIdx]-startNs) / 1e6
+		stats.riseTimeMs = riseTimeMs(samples, timestampsNs, stats.max, opts.riseLowPct, opts.riseHighPct, peakIdx)
+		stats.dwellMs = dwellTimeMs(samples, timestampsNs, stats.max, opts.dwellFraction)
+		stats.impulse = impulse(samples, timestampsNs)
+	}
+	stats.samplesBelowDuringDwell = samplesBelowDuringDwell(samples, stats.max, opts.dwellFraction)
+
+	if opts.sampleRateHz > 0 {
+		dtSec := 1 / float64(opts.sampleRateHz)
+		stats.impulseNs = impulseFixedDt(samples, dtSec)
+		stats.contactDurationMs = contactDurationMs(samples, opts.zeroThreshold, dtSec)
+	}
+
+	peakSamples := samples
+	if opts.smoothingWindow > 1 {
+		peakSamples = movingAverage(samples, opts.smoothingWindow)
+	}
+	stats.peakCount = countPeaks(peakSamples, opts.peakProminence)
+
+	return stats
+}
+
+// windowSamples restricts samples/timestampsNs to the trailing windowMs
+// milliseconds of the capture. windowMs <= 0 returns the inputs unchanged.
+func windowSamples(samples []float64, timestampsNs []int64, windowMs int) ([]float64, []int64) {
+	if windowMs <= 0 || len(timestampsNs) != len(samples) || len(samples) == 0 {
+		return samples, timestampsNs
+	}
+	cutoff := timestampsNs[len(timestampsNs)-1] - int64(windowMs)*1e6
+	start := 0
+	for start < len(timestampsNs) && timestampsNs[start] < cutoff {
+		start++
+	}
+	return samples[start:], timestampsNs[start:]
+}
+
+// movingAverage returns a simple trailing moving average of samples over the
+// given window size, used to low-pass filter noise before peak counting.
+func movingAverage(samples []float64, window int) []float64 {
+	smoothed := make([]float64, len(samples))
+	var sum float64
+	for i, v := range samples {
+		sum += v
+		if i >= window {
+			sum -= samples[i-window]
+		}
+		n := window
+		if i+1 < n {
+			n = i + 1
+		}
+		smoothed[i] = sum / float64(n)
+	}
+	return smoothed
+}
+
+// countPeaks counts local maxima in samples whose prominence -- the rise
+// above the lowest point since the previously counted peak -- is at least
+// prominence. A prominence of 0 counts every local max.
+func countPeaks(samples []float64, prominence float64) int {
+	if len(samples) < 3 {
+		return 0
+	}
+
+	var candidates []int
+	for i := 1; i < len(samples)-1; i++ {
+		if samples[i] > samples[i-1] && samples[i] > samples[i+1] {
+			candidates = append(candidates, i)
+		}
+	}
+
+	count := 0
+	lastPeakIdx := 0
+	for _, idx := range candidates {
+		floor := samples[lastPeakIdx]
+		for j := lastPeakIdx; j <= idx; j++ {
+			if samples[j] < floor {
+				floor = samples[j]
+			}
+		}
+		if samples[idx]-floor >= prominence {
+			count++
+			lastPeakIdx = idx
+		}
+	}
+	return count
+}
+
+// samplesBelowDuringDwell counts samples that dip below dwellFraction*peak
+// between the first and last sample that reached it -- re-contacts or drips
+// during an otherwise sustained dwell show up here.
+func samplesBelowDuringDwell(samples []float64, peak, dwellFraction float64) int {
+	if peak <= 0 {
+		return 0
+	}
+	thresh := peak * dwellFraction
+	first, last := -1, -1
+	for i, v := range samples {
+		if v >= thresh {
+			if first == -1 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first == -1 {
+		return 0
+	}
+
+	count := 0
+	for i := first; i <= last; i++ {
+		if samples[i] < thresh {
+			count++
+		}
+	}
+	return count
+}
+
+// riseTimeMs returns the interpolated time to rise from riseLowPct to
+// riseHighPct of peak, searching samples[0:peakIdx+1].
+func riseTimeMs(samples []float64, timestampsNs []int64, peak, riseLowPct, riseHighPct float64, peakIdx int) float64 {
+	if peak <= 0 {
+		return 0
+	}
+	lowThresh := peak * riseLowPct / 100
+	highThresh := peak * riseHighPct / 100
+
+	lowCrossNs, ok := interpolateCrossing(samples, timestampsNs, peakIdx, lowThresh)
+	if !ok {
+		return 0
+	}
+	highCrossNs, ok := interpolateCrossing(samples, timestampsNs, peakIdx, highThresh)
+	if !ok {
+		return 0
+	}
+	return float64(highCrossNs-lowCrossNs) / 1e6
+}
+
+// interpolateCrossing finds the first rising-edge crossing of thresh within
+// samples[0:upTo+1], linearly interpolating between the bracketing samples.
+func interpolateCrossing(samples []float64, timestampsNs []int64, upTo int, thresh float64) (int64, bool) {
+	for i := 1; i <= upTo; i++ {
+		if samples[i-1] < thresh && samples[i] >= thresh {
+			frac := (thresh - samples[i-1]) / (samples[i] - samples[i-1])
+			dtNs := timestampsNs[i] - timestampsNs[i-1]
+			return timestampsNs[i-1] + int64(frac*float64(dtNs)), true
+		}
+	}
+	if samples[0] >= thresh {
+		return timestampsNs[0], true
+	}
+	return 0, false
+}
+
+// dwellTimeMs sums the elapsed time between consecutive samples that are
+// both at or above dwellFraction*peak.
+func dwellTimeMs(samples []float64, timestampsNs []int64, peak, dwellFraction float64) float64 {
+	if peak <= 0 {
+		return 0
+	}
+	thresh := peak * dwellFraction
+	var dwellNs int64
+	for i := 1; i < len(samples); i++ {
+		if samples[i-1] >= thresh && samples[i] >= thresh {
+			dwellNs += timestampsNs[i] - timestampsNs[i-1]
+		}
+	}
+	return float64(dwellNs) / 1e6
+}
+
+// impulse is the trapezoidal integral of force over time, in newton-seconds.
+func impulse(samples []float64, timestampsNs []int64) float64 {
+	var total float64
+	for i := 1; i < len(samples); i++ {
+		dtSec := float64(timestampsNs[i]-timestampsNs[i-1]) / 1e9
+		total += 0.5 * (samples[i-1] + samples[i]) * dtSec
+	}
+	return total
+}
+
+// impulseFixedDt is impulse's trapezoidal integral but assumes a constant dt
+// (1/sampleRateHz) between samples instead of the real inter-sample
+// timestamps, so it isn't thrown off by sample-rate jitter the way impulse
+// can be.
+func impulseFixedDt(samples []float64, dtSec float64) float64 {
+	var total float64
+	for i := 1; i < len(samples); i++ {
+		total += 0.5 * (samples[i-1] + samples[i]) * dtSec
+	}
+	return total
+}
+
+// contactDurationMs counts samples at or above zeroThreshold and multiplies
+// by the nominal sample spacing dtSec, estimating total time in contact
+// without depending on real inter-sample timestamps.
+func contactDurationMs(samples []float64, zeroThreshold, dtSec float64) float64 {
+	count := 0
+	for _, v := range samples {
+		if v >= zeroThreshold {
+			count++
+		}
+	}
+	return float64(count) * dtSec * 1000
+}
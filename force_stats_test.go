@@ -0,0 +1,204 @@
+package kettlecycletest
+
+import (
+	"math"
+	"testing"
+)
+
+func approxEqual(a, b, tol float64) bool {
+	return math.Abs(a-b) <= tol
+}
+
+func TestComputeForceStats(t *testing.T) {
+	t.Run("empty samples returns zero value", func(t *testing.T) {
+		stats := computeForceStats(nil, nil, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9})
+		if stats.sampleCount != 0 {
+			t.Errorf("expected sampleCount=0, got %d", stats.sampleCount)
+		}
+	})
+
+	t.Run("basic min/max/mean/rms", func(t *testing.T) {
+		samples := []float64{0, 50, 100, 50, 0}
+		times := []int64{0, 10_000_000, 20_000_000, 30_000_000, 40_000_000} // 10ms steps
+		stats := computeForceStats(samples, times, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9})
+
+		if stats.sampleCount != 5 {
+			t.Errorf("expected sampleCount=5, got %d", stats.sampleCount)
+		}
+		if stats.min != 0 {
+			t.Errorf("expected min=0, got %v", stats.min)
+		}
+		if stats.max != 100 {
+			t.Errorf("expected max=100, got %v", stats.max)
+		}
+		if stats.peakToPeak != 100 {
+			t.Errorf("expected peakToPeak=100, got %v", stats.peakToPeak)
+		}
+		wantMean := 40.0
+		if !approxEqual(stats.mean, wantMean, 0.001) {
+			t.Errorf("expected mean=%v, got %v", wantMean, stats.mean)
+		}
+		if stats.captureDurationMs != 40 {
+			t.Errorf("expected captureDurationMs=40, got %v", stats.captureDurationMs)
+		}
+		if stats.timeToPeakMs != 20 {
+			t.Errorf("expected timeToPeakMs=20, got %v", stats.timeToPeakMs)
+		}
+	})
+
+	t.Run("rise time interpolates between 10% and 90% of peak", func(t *testing.T) {
+		// Linear ramp 0 -> 100 over 100ms (10 steps of 10ms), then flat.
+		samples := make([]float64, 0, 11)
+		times := make([]int64, 0, 11)
+		for i := 0; i <= 10; i++ {
+			samples = append(samples, float64(i*10))
+			times = append(times, int64(i)*10_000_000)
+		}
+		stats := computeForceStats(samples, times, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9})
+
+		// 10% of peak (100) = 10, crossed exactly at sample index 1 (t=10ms).
+		// 90% of peak = 90, crossed exactly at sample index 9 (t=90ms).
+		wantRiseMs := 80.0
+		if !approxEqual(stats.riseTimeMs, wantRiseMs, 0.001) {
+			t.Errorf("expected riseTimeMs=%v, got %v", wantRiseMs, stats.riseTimeMs)
+		}
+	})
+
+	t.Run("dwell time sums intervals at or above dwell fraction of peak", func(t *testing.T) {
+		samples := []float64{0, 100, 100, 100, 0}
+		times := []int64{0, 10_000_000, 20_000_000, 30_000_000, 40_000_000}
+		stats := computeForceStats(samples, times, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9})
+
+		wantDwellMs := 20.0 // two 10ms intervals where both endpoints are >= 90
+		if !approxEqual(stats.dwellMs, wantDwellMs, 0.001) {
+			t.Errorf("expected dwellMs=%v, got %v", wantDwellMs, stats.dwellMs)
+		}
+	})
+
+	t.Run("impulse is the trapezoidal integral over time", func(t *testing.T) {
+		samples := []float64{0, 100}
+		times := []int64{0, 1_000_000_000} // 1 second
+		stats := computeForceStats(samples, times, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9})
+
+		wantImpulse := 50.0 // 0.5 * (0+100) * 1s
+		if !approxEqual(stats.impulse, wantImpulse, 0.001) {
+			t.Errorf("expected impulse=%v, got %v", wantImpulse, stats.impulse)
+		}
+	})
+
+	t.Run("stddev of a constant series is zero", func(t *testing.T) {
+		samples := []float64{50, 50, 50, 50}
+		stats := computeForceStats(samples, nil, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9})
+
+		if !approxEqual(stats.stddev, 0, 0.001) {
+			t.Errorf("expected stddev=0, got %v", stats.stddev)
+		}
+	})
+
+	t.Run("stddev of a repeated non-round value never returns NaN", func(t *testing.T) {
+		// A sustained dwell at a realistic repeating-decimal reading, like
+		// 166.85 N held for 100 samples: the naive sum(x^2)/n - mean^2
+		// formula can drive the bracketed term slightly negative here due
+		// to floating-point cancellation, producing NaN from math.Sqrt.
+		samples := make([]float64, 100)
+		for i := range samples {
+			samples[i] = 166.85
+		}
+		stats := computeForceStats(samples, nil, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9})
+
+		if math.IsNaN(stats.stddev) {
+			t.Fatal("expected stddev to not be NaN for a tightly clustered repeated value")
+		}
+		if !approxEqual(stats.stddev, 0, 1e-9) {
+			t.Errorf("expected stddev=0, got %v", stats.stddev)
+		}
+	})
+
+	t.Run("peak_count counts local maxima by default prominence", func(t *testing.T) {
+		samples := []float64{0, 10, 0, 20, 0, 5, 0}
+		stats := computeForceStats(samples, nil, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9})
+
+		if stats.peakCount != 3 {
+			t.Errorf("expected peakCount=3, got %d", stats.peakCount)
+		}
+	})
+
+	t.Run("peak_count filters out low-prominence peaks", func(t *testing.T) {
+		samples := []float64{0, 10, 0, 20, 0, 5, 0}
+		stats := computeForceStats(samples, nil, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9, peakProminence: 8})
+
+		// The peak of 5 only rises 5 above its surrounding floor of 0, so it's
+		// filtered out at a prominence threshold of 8; the other two survive.
+		if stats.peakCount != 2 {
+			t.Errorf("expected peakCount=2, got %d", stats.peakCount)
+		}
+	})
+
+	t.Run("smoothing_window suppresses noise-driven peaks before counting", func(t *testing.T) {
+		samples := []float64{0, 10, 9, 10, 9, 10, 0}
+		stats := computeForceStats(samples, nil, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9})
+		if stats.peakCount != 3 {
+			t.Errorf("expected peakCount=3 without smoothing, got %d", stats.peakCount)
+		}
+
+		smoothed := computeForceStats(samples, nil, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9, smoothingWindow: 5})
+		if smoothed.peakCount != 1 {
+			t.Errorf("expected peakCount=1 after smoothing, got %d", smoothed.peakCount)
+		}
+	})
+
+	t.Run("impulse_ns integrates using a fixed 1/sampleRateHz dt", func(t *testing.T) {
+		// Real timestamps are irregular (jitter), but impulse_ns must ignore
+		// them and use the nominal 10ms spacing implied by sampleRateHz=100.
+		samples := []float64{0, 100}
+		times := []int64{0, 7_000_000} // 7ms elapsed, not the nominal 10ms
+		stats := computeForceStats(samples, times, statsOptions{
+			riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9,
+			sampleRateHz: 100,
+		})
+
+		wantImpulseNs := 0.5 // 0.5 * (0+100) * (1/100s)
+		if !approxEqual(stats.impulseNs, wantImpulseNs, 0.001) {
+			t.Errorf("expected impulseNs=%v, got %v", wantImpulseNs, stats.impulseNs)
+		}
+	})
+
+	t.Run("impulse_ns is zero when sampleRateHz is unset", func(t *testing.T) {
+		samples := []float64{0, 100}
+		times := []int64{0, 1_000_000_000}
+		stats := computeForceStats(samples, times, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9})
+
+		if stats.impulseNs != 0 {
+			t.Errorf("expected impulseNs=0 without a configured sampleRateHz, got %v", stats.impulseNs)
+		}
+	})
+
+	t.Run("contact_duration_ms counts samples at or above zeroThreshold times the nominal dt", func(t *testing.T) {
+		samples := []float64{0, 2, 10, 10, 2, 0}
+		stats := computeForceStats(samples, nil, statsOptions{
+			riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9,
+			sampleRateHz: 100, zeroThreshold: 5,
+		})
+
+		// Two samples (the pair of 10s) are >= the zeroThreshold of 5, each
+		// worth 1/100s = 10ms.
+		wantContactMs := 20.0
+		if !approxEqual(stats.contactDurationMs, wantContactMs, 0.001) {
+			t.Errorf("expected contactDurationMs=%v, got %v", wantContactMs, stats.contactDurationMs)
+		}
+	})
+
+	t.Run("analysis_window_ms restricts stats to the trailing window", func(t *testing.T) {
+		samples := []float64{100, 0, 0, 0, 0}
+		times := []int64{0, 10_000_000, 20_000_000, 30_000_000, 40_000_000}
+		stats := computeForceStats(samples, times, statsOptions{riseLowPct: 10, riseHighPct: 90, dwellFraction: 0.9, analysisWindowMs: 15})
+
+		// Only the trailing 15ms (samples at t=30ms and t=40ms) should remain.
+		if stats.sampleCount != 2 {
+			t.Errorf("expected sampleCount=2, got %d", stats.sampleCount)
+		}
+		if stats.max != 0 {
+			t.Errorf("expected max=0 (peak sample windowed out), got %v", stats.max)
+		}
+	})
+}
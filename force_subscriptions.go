@@ -0,0 +1,104 @@
+package kettlecycletest
+
+import "fmt"
+
+// subscriptionBufferCap bounds how many samples a subscription will buffer
+// between polls before it starts dropping the oldest ones.
+const subscriptionBufferCap = 2000
+
+// forceSamplePoint is a single timestamped force reading, as delivered to
+// subscribers via poll.
+type forceSamplePoint struct {
+	TimestampNs int64   `json:"timestamp_ns"`
+	Force       float64 `json:"force"`
+}
+
+// forceSubscription is a per-caller ring buffer of samples collected since
+// the last poll. Callers must hold the owning forceSensor's mu.
+type forceSubscription struct {
+	samples []forceSamplePoint
+}
+
+// push appends p to the subscription's buffer, dropping the oldest sample
+// once subscriptionBufferCap is reached. It reports whether a sample was
+// dropped so callers can account for it (e.g. in metrics).
+func (s *forceSubscription) push(p forceSamplePoint) (dropped bool) {
+	if len(s.samples) >= subscriptionBufferCap {
+		s.samples = s.samples[1:]
+		dropped = true
+	}
+	s.samples = append(s.samples, p)
+	return dropped
+}
+
+func (s *forceSubscription) drain() []forceSamplePoint {
+	drained := s.samples
+	s.samples = nil
+	return drained
+}
+
+// handleSubscribe registers a new subscription and returns its subscription_id.
+func (fs *forceSensor) handleSubscribe() (map[string]interface{}, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.subscriptions == nil {
+		fs.subscriptions = make(map[string]*forceSubscription)
+	}
+	fs.nextSubID++
+	id := fmt.Sprintf("sub-%d", fs.nextSubID)
+	fs.subscriptions[id] = &forceSubscription{}
+
+	return map[string]interface{}{"subscription_id": id}, nil
+}
+
+func (fs *forceSensor) handleUnsubscribe(cmd map[string]interface{}) (map[string]interface{}, error) {
+	id, ok := cmd["subscription_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid 'subscription_id' field")
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if _, ok := fs.subscriptions[id]; !ok {
+		return nil, fmt.Errorf("unknown subscription_id: %s", id)
+	}
+	delete(fs.subscriptions, id)
+
+	return map[string]interface{}{"status": "unsubscribed"}, nil
+}
+
+// handlePoll drains and returns every sample collected since the
+// subscription's last poll.
+func (fs *forceSensor) handlePoll(cmd map[string]interface{}) (map[string]interface{}, error) {
+	id, ok := cmd["subscription_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid 'subscription_id' field")
+	}
+
+	fs.mu.Lock()
+	sub, ok := fs.subscriptions[id]
+	var drained []forceSamplePoint
+	if ok {
+		drained = sub.drain()
+	}
+	fs.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown subscription_id: %s", id)
+	}
+
+	samples := make([]interface{}, len(drained))
+	for i, p := range drained {
+		samples[i] = map[string]interface{}{
+			"timestamp_ns": p.TimestampNs,
+			"force":        p.Force,
+		}
+	}
+
+	return map[string]interface{}{
+		"samples": samples,
+		"count":   len(samples),
+	}, nil
+}
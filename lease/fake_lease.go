@@ -0,0 +1,71 @@
+package lease
+
+import (
+	"context"
+	"sync"
+)
+
+// FakeLease is an in-memory Lease for tests that need to simulate
+// acquisition, contention, and loss without a real file or TCP backend.
+type FakeLease struct {
+	mu         sync.Mutex
+	held       bool
+	acquireErr error
+	renewErr   error
+}
+
+// NewFake returns a FakeLease that acquires and renews successfully until
+// told otherwise.
+func NewFake() *FakeLease {
+	return &FakeLease{}
+}
+
+func (l *FakeLease) Acquire(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.acquireErr != nil {
+		return l.acquireErr
+	}
+	l.held = true
+	return nil
+}
+
+func (l *FakeLease) Renew(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.renewErr != nil {
+		l.held = false
+		return l.renewErr
+	}
+	return nil
+}
+
+func (l *FakeLease) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.held = false
+	return nil
+}
+
+// SimulateLoss makes every Renew call from here on fail with err, as if
+// another holder took the lease out from under this one.
+func (l *FakeLease) SimulateLoss(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.renewErr = err
+}
+
+// SimulateContention makes every future Acquire call fail with err, as if
+// another instance already holds the lease.
+func (l *FakeLease) SimulateContention(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.acquireErr = err
+}
+
+// Held reports whether the lease is currently believed to be held.
+func (l *FakeLease) Held() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.held
+}
@@ -0,0 +1,100 @@
+// Package lease implements the leader-election guard that keeps two
+// controller instances (e.g. a hot-spare configured by mistake) from
+// driving the same arm at once. A Lease must be held before a trial
+// starts cycling and renewed on a cadence; a failed renewal means
+// leadership is lost and the holder must stop driving hardware
+// immediately.
+package lease
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// Backend selects which Lease implementation Config.Lease builds.
+type Backend string
+
+const (
+	BackendFile Backend = "file"
+	BackendTCP  Backend = "tcp"
+)
+
+const (
+	defaultTTLSeconds           = 15
+	defaultRenewIntervalSeconds = 5
+)
+
+// Config is the Config.Lease block.
+type Config struct {
+	Backend              Backend `json:"backend"`
+	PathOrAddr           string  `json:"path_or_addr"`
+	TTLSeconds           int     `json:"ttl_seconds,omitempty"`
+	RenewIntervalSeconds int     `json:"renew_interval_seconds,omitempty"`
+}
+
+// ApplyDefaults fills in zero-valued fields on c with their defaults, in
+// place. Callers that need the effective renew interval (e.g. to schedule
+// their own renewal ticks) must call this before reading it.
+func (c *Config) ApplyDefaults() {
+	if c.TTLSeconds <= 0 {
+		c.TTLSeconds = defaultTTLSeconds
+	}
+	if c.RenewIntervalSeconds <= 0 {
+		c.RenewIntervalSeconds = defaultRenewIntervalSeconds
+	}
+}
+
+// Validate checks that c is well-formed. Call ApplyDefaults first.
+func (c *Config) Validate(path string) error {
+	switch c.Backend {
+	case BackendFile, BackendTCP:
+	default:
+		return fmt.Errorf(`%s: lease.backend must be "file" or "tcp"`, path)
+	}
+	if c.PathOrAddr == "" {
+		return fmt.Errorf("%s: lease.path_or_addr is required", path)
+	}
+	if c.RenewIntervalSeconds >= c.TTLSeconds {
+		return fmt.Errorf("%s: lease.renew_interval_seconds must be less than ttl_seconds", path)
+	}
+	return nil
+}
+
+// Lease is a renewable leader-election handle. Acquire must succeed before
+// a trial starts cycling; Renew must be called at
+// Config.RenewIntervalSeconds; a Renew error means leadership is lost and
+// the caller must stop driving hardware immediately. Release gives up the
+// lease early, e.g. when a trial ends normally.
+type Lease interface {
+	Acquire(ctx context.Context) error
+	Renew(ctx context.Context) error
+	Release(ctx context.Context) error
+}
+
+// New builds the Lease backend conf.Backend selects. conf should already
+// have ApplyDefaults called on it.
+func New(conf Config, holderID string, logger logging.Logger) (Lease, error) {
+	switch conf.Backend {
+	case BackendFile:
+		return newFileLease(conf, holderID), nil
+	case BackendTCP:
+		return newTCPLease(conf, holderID, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown lease backend %q", conf.Backend)
+	}
+}
+
+// RenewInterval is the configured cadence at which the holder must call
+// Renew to keep the lease.
+func (c Config) RenewInterval() time.Duration {
+	return time.Duration(c.RenewIntervalSeconds) * time.Second
+}
+
+// TTL is the configured duration a lease is valid for without a successful
+// renewal.
+func (c Config) TTL() time.Duration {
+	return time.Duration(c.TTLSeconds) * time.Second
+}
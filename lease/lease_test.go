@@ -0,0 +1,122 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConfigApplyDefaults(t *testing.T) {
+	c := Config{}
+	c.ApplyDefaults()
+	if c.TTLSeconds != defaultTTLSeconds {
+		t.Errorf("TTLSeconds = %d, want %d", c.TTLSeconds, defaultTTLSeconds)
+	}
+	if c.RenewIntervalSeconds != defaultRenewIntervalSeconds {
+		t.Errorf("RenewIntervalSeconds = %d, want %d", c.RenewIntervalSeconds, defaultRenewIntervalSeconds)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		conf    Config
+		wantErr string
+	}{
+		{
+			name:    "missing backend",
+			conf:    Config{PathOrAddr: "/tmp/x", TTLSeconds: 15, RenewIntervalSeconds: 5},
+			wantErr: "lease.backend",
+		},
+		{
+			name:    "missing path_or_addr",
+			conf:    Config{Backend: BackendFile, TTLSeconds: 15, RenewIntervalSeconds: 5},
+			wantErr: "lease.path_or_addr",
+		},
+		{
+			name:    "renew interval not less than ttl",
+			conf:    Config{Backend: BackendFile, PathOrAddr: "/tmp/x", TTLSeconds: 5, RenewIntervalSeconds: 5},
+			wantErr: "renew_interval_seconds",
+		},
+		{
+			name: "valid",
+			conf: Config{Backend: BackendFile, PathOrAddr: "/tmp/x", TTLSeconds: 15, RenewIntervalSeconds: 5},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.conf.Validate("lease")
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.wantErr)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFakeLeaseAcquireRenewRelease(t *testing.T) {
+	l := NewFake()
+	ctx := context.Background()
+
+	if l.Held() {
+		t.Fatal("expected not held before Acquire")
+	}
+	if err := l.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if !l.Held() {
+		t.Fatal("expected held after Acquire")
+	}
+	if err := l.Renew(ctx); err != nil {
+		t.Fatalf("Renew failed: %v", err)
+	}
+	if err := l.Release(ctx); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if l.Held() {
+		t.Fatal("expected not held after Release")
+	}
+}
+
+func TestFakeLeaseSimulateLoss(t *testing.T) {
+	l := NewFake()
+	ctx := context.Background()
+	if err := l.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	lossErr := errors.New("lease taken over")
+	l.SimulateLoss(lossErr)
+
+	if err := l.Renew(ctx); !errors.Is(err, lossErr) {
+		t.Fatalf("Renew error = %v, want %v", err, lossErr)
+	}
+	if l.Held() {
+		t.Fatal("expected not held after a failed renewal")
+	}
+}
+
+func TestFakeLeaseSimulateContention(t *testing.T) {
+	l := NewFake()
+	ctx := context.Background()
+
+	contentionErr := errors.New("already held")
+	l.SimulateContention(contentionErr)
+
+	if err := l.Acquire(ctx); !errors.Is(err, contentionErr) {
+		t.Fatalf("Acquire error = %v, want %v", err, contentionErr)
+	}
+	if l.Held() {
+		t.Fatal("expected not held after a failed acquisition")
+	}
+}
@@ -0,0 +1,125 @@
+package lease
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// tcpPutRequest is the body of every PUT to conf.PathOrAddr.
+type tcpPutRequest struct {
+	HolderID   string `json:"holder_id"`
+	TTLSeconds int    `json:"ttl_seconds"`
+}
+
+// tcpPutResponse is the server's reply: epoch must increase by exactly one
+// each time a new holder takes the lease, and stay the same across a
+// renewal by the current holder.
+type tcpPutResponse struct {
+	Epoch int64 `json:"epoch"`
+}
+
+// tcpLease is the "tcp" backend: a lease server reachable by HTTP PUT,
+// modeled on the node-lease pattern used by Kubernetes leader election.
+// Acquire and Renew are the same request; a renewal is distinguished from
+// a takeover by the epoch differing from what this holder last saw,
+// which means someone else has since taken the lease.
+type tcpLease struct {
+	addr     string
+	holderID string
+	ttl      time.Duration
+	client   *http.Client
+	logger   logging.Logger
+
+	mu        sync.Mutex
+	lastEpoch int64
+	held      bool
+}
+
+func newTCPLease(conf Config, holderID string, logger logging.Logger) *tcpLease {
+	return &tcpLease{
+		addr:     conf.PathOrAddr,
+		holderID: holderID,
+		ttl:      conf.TTL(),
+		client:   &http.Client{Timeout: 5 * time.Second},
+		logger:   logger,
+	}
+}
+
+func (l *tcpLease) Acquire(ctx context.Context) error {
+	epoch, err := l.put(ctx)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.lastEpoch = epoch
+	l.held = true
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *tcpLease) Renew(ctx context.Context) error {
+	l.mu.Lock()
+	if !l.held {
+		l.mu.Unlock()
+		return fmt.Errorf("lease at %s is not held", l.addr)
+	}
+	lastEpoch := l.lastEpoch
+	l.mu.Unlock()
+
+	epoch, err := l.put(ctx)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if epoch != lastEpoch {
+		l.held = false
+		return fmt.Errorf("lease at %s moved to epoch %d (held at %d): leadership lost", l.addr, epoch, lastEpoch)
+	}
+	return nil
+}
+
+func (l *tcpLease) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// Best-effort: there's no DELETE in the node-lease PUT protocol, so a
+	// released lease is reclaimed once its TTL elapses on the server side.
+	l.held = false
+	return nil
+}
+
+func (l *tcpLease) put(ctx context.Context) (int64, error) {
+	body, err := json.Marshal(tcpPutRequest{HolderID: l.holderID, TTLSeconds: int(l.ttl.Seconds())})
+	if err != nil {
+		return 0, fmt.Errorf("marshaling lease request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, l.addr, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("building lease request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("lease PUT %s: %w", l.addr, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("lease PUT %s: unexpected status %s", l.addr, resp.Status)
+	}
+
+	var out tcpPutResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, fmt.Errorf("decoding lease response from %s: %w", l.addr, err)
+	}
+	return out.Epoch, nil
+}
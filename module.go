@@ -1,17 +1,25 @@
 package kettlecycletest
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"net/http"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/expfmt"
 	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/components/sensor"
 	toggleswitch "go.viam.com/rdk/components/switch"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	generic "go.viam.com/rdk/services/generic"
+
+	"kettlecycletest/lease"
+	"kettlecycletest/program"
 )
 
 var Controller = resource.NewModel("viamdemo", "kettle-cycle-test", "controller")
@@ -29,14 +37,98 @@ type Config struct {
 	RestingPosition  string `json:"resting_position"`
 	PourPrepPosition string `json:"pour_prep_position"`
 	ForceSensor      string `json:"force_sensor,omitempty"`
+	TrialLogPath     string `json:"trial_log_path,omitempty"`   // optional: append one JSONL record per trial lifecycle event to this file
+	MetricsAddr      string `json:"metrics_addr,omitempty"`     // optional: serve Prometheus metrics on this address (e.g. ":9102")
+	EventLogPath     string `json:"event_log_path,omitempty"`   // optional: append one JSONL record per broadcast event to this file
+	WebhookURL       string `json:"webhook_url,omitempty"`      // optional: POST every broadcast event to this URL
+	TrialStatePath   string `json:"trial_state_path,omitempty"` // optional: persist the active trial here so it survives a restart
+
+	// Program configures the steps handleExecuteCycle runs; see package
+	// program. At most one of Program and ProgramPath should be set. If
+	// neither is set, the historical pour_prep->resting sequence is used.
+	Program     string `json:"program,omitempty"`      // optional: inline JSON/YAML cycle program
+	ProgramPath string `json:"program_path,omitempty"` // optional: path to a JSON/YAML cycle program file
+
+	// Switches declares extra named switches a program's move_to_switch
+	// steps may reference, beyond the built-in "pour_prep" and "resting"
+	// (bound from PourPrepPosition and RestingPosition). Keys are the names
+	// steps use; values are the switch's dependency name.
+	Switches map[string]string `json:"switches,omitempty"`
+
+	// Lease configures the leader-election guard that keeps a second
+	// controller instance from driving the same arm at once. If unset, no
+	// leader election is performed. See package lease.
+	Lease *lease.Config `json:"lease,omitempty"`
+
+	// Cycle pacing, all optional; zero values fall back to the defaults below.
+	MinCycleIntervalMs      int `json:"min_cycle_interval_ms,omitempty"`      // dwell after a successful cycle
+	MaxCycleIntervalMs      int `json:"max_cycle_interval_ms,omitempty"`      // ceiling on a trial's requested interval_ms
+	FailureBackoffBaseMs    int `json:"failure_backoff_base_ms,omitempty"`    // backoff after the first consecutive failure
+	FailureBackoffMaxMs     int `json:"failure_backoff_max_ms,omitempty"`     // backoff ceiling
+	ConsecutiveFailureLimit int `json:"consecutive_failure_limit,omitempty"` // trial goes "degraded" after this many failures in a row
 }
 
+const (
+	defaultMinCycleIntervalMs      = 1000
+	defaultMaxCycleIntervalMs      = 60_000
+	defaultFailureBackoffBaseMs    = 500
+	defaultFailureBackoffMaxMs     = 30_000
+	defaultConsecutiveFailureLimit = 5
+)
+
 type trialState struct {
 	trialID     string
 	cycleCount  int
 	startedAt   time.Time
 	lastCycleAt time.Time
 	stopCh      chan struct{}
+
+	// Scheduled-run configuration, set by handleStart.
+	targetCycles int           // 0 = unbounded
+	intervalMs   int           // dwell between cycles
+	maxDuration  time.Duration // 0 = no wall-clock cap
+	abortOnError bool
+
+	// Scheduled-run progress, updated by cycleLoop.
+	currentCycle        int
+	completedCycles     int
+	failedCycles        int
+	lastCycleDurationMs int64
+	nextCycleAt         time.Time
+	terminalState       string // "", "completed", "aborted", or "failed"
+	records             []cycleRecord
+
+	// consecutiveFailures counts unbroken handleExecuteCycle failures, driving
+	// both the backoff ladder and the degraded transition; it resets to 0 on
+	// the next success. degraded is set once consecutiveFailures reaches
+	// Config.ConsecutiveFailureLimit, at which point cycleLoop stops without
+	// clearing activeTrial so handleStatus keeps reporting the failing trial
+	// until handleResume restarts it.
+	consecutiveFailures int
+	degraded            bool
+
+	// lostLeadership is set by cycleLoop when a configured lease fails to
+	// renew, mirroring degraded: cycleLoop stops without clearing
+	// activeTrial, and handleResume re-acquires the lease before restarting
+	// it.
+	lostLeadership bool
+}
+
+// cycleRecord is one entry in a trial's ring buffer of recent cycle outcomes.
+type cycleRecord struct {
+	index      int
+	startedAt  time.Time
+	durationMs int64
+	err        string
+}
+
+const maxCycleRecords = 50
+
+func (t *trialState) pushRecord(r cycleRecord) {
+	if len(t.records) >= maxCycleRecords {
+		t.records = t.records[1:]
+	}
+	t.records = append(t.records, r)
 }
 
 func (cfg *Config) Validate(path string) ([]string, []string, error) {
@@ -53,6 +145,9 @@ func (cfg *Config) Validate(path string) ([]string, []string, error) {
 	if cfg.ForceSensor != "" {
 		deps = append(deps, cfg.ForceSensor)
 	}
+	for _, depName := range cfg.Switches {
+		deps = append(deps, depName)
+	}
 	return deps, nil, nil
 }
 
@@ -68,11 +163,75 @@ type kettleCycleTestController struct {
 	pourPrep    toggleswitch.Switch
 	forceSensor sensor.Sensor // optional, may be nil
 
+	// switches holds every switch a program's move_to_switch step may
+	// reference: the built-in "resting"/"pour_prep" plus any from
+	// Config.Switches.
+	switches map[string]toggleswitch.Switch
+
+	// program is the compiled, validated sequence handleExecuteCycle runs,
+	// either from Config.Program/ProgramPath or program.Default().
+	program *program.Program
+
 	cancelCtx  context.Context
 	cancelFunc func()
 
+	// trialStore persists trial lifecycle events if configured; nil otherwise.
+	trialStore *trialStore
+
+	// trialStateStore persists activeTrial itself if configured, so a crash
+	// or restart can rehydrate it instead of losing the trial; nil otherwise.
+	trialStateStore TrialStateStore
+
+	// registry is the Prometheus registry metrics are registered to and
+	// handleMetrics gathers from; metricsRegistry in production, an isolated
+	// *prometheus.Registry in tests that want to avoid sharing counter state
+	// with other tests. metrics holds the collectors registered to it.
+	registry *prometheus.Registry
+	metrics  *controllerMetrics
+
+	// metricsServer serves registry on cfg.MetricsAddr; nil if unconfigured.
+	metricsServer *http.Server
+
+	// events broadcasts lifecycle events to its registered sinks; events is
+	// always non-nil, but eventFile is nil unless cfg.EventLogPath is set.
+	events    *eventBroadcaster
+	eventRing *ringEventSink
+	eventFile *eventFileSink
+
+	// clk is real time in production; tests substitute a fakeClock to drive
+	// cycleLoop's pacing and backoff deterministically.
+	clk clock
+
+	// lease is the leader-election guard cycleLoop holds for the duration of
+	// a trial; nil unless Config.Lease is set. leaseRenewInterval is how
+	// often cycleLoop calls lease.Renew.
+	lease              lease.Lease
+	leaseRenewInterval time.Duration
+
 	mu          sync.Mutex
 	activeTrial *trialState
+
+	// leaseHeld reports whether lease is currently believed to be held; it
+	// gates handleExecuteCycle so a controller that lost leadership (or never
+	// acquired it) refuses to drive the arm. Always true if lease is nil.
+	leaseHeld bool
+}
+
+// ControllerOption customizes NewController beyond Config. Production code
+// never needs one; it exists so tests can exercise the module's metrics
+// without sharing counter state with the process-wide metricsRegistry other
+// tests register against.
+type ControllerOption func(*controllerOptions)
+
+type controllerOptions struct {
+	registry *prometheus.Registry
+}
+
+// WithMetricsRegistry overrides the Prometheus registry metrics are
+// registered to and handleMetrics gathers from; it defaults to the
+// process-wide metricsRegistry.
+func WithMetricsRegistry(reg *prometheus.Registry) ControllerOption {
+	return func(o *controllerOptions) { o.registry = reg }
 }
 
 func newKettleCycleTestController(ctx context.Context, deps resource.Dependencies, rawConf resource.Config, logger logging.Logger) (resource.Resource, error) {
@@ -85,7 +244,16 @@ func newKettleCycleTestController(ctx context.Context, deps resource.Dependencie
 
 }
 
-func NewController(ctx context.Context, deps resource.Dependencies, name resource.Name, conf *Config, logger logging.Logger) (resource.Resource, error) {
+func NewController(ctx context.Context, deps resource.Dependencies, name resource.Name, conf *Config, logger logging.Logger, opts ...ControllerOption) (resource.Resource, error) {
+	options := controllerOptions{registry: metricsRegistry}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	metrics := defaultControllerMetrics
+	if options.registry != metricsRegistry {
+		metrics = newControllerMetrics(options.registry)
+	}
+
 	a, err := arm.FromDependencies(deps, conf.Arm)
 	if err != nil {
 		return nil, fmt.Errorf("getting arm: %w", err)
@@ -110,22 +278,270 @@ func NewController(ctx context.Context, deps resource.Dependencies, name resourc
 		logger.Infof("controller using force sensor: %s", conf.ForceSensor)
 	}
 
+	switches := map[string]toggleswitch.Switch{
+		"resting":   resting,
+		"pour_prep": pourPrep,
+	}
+	for switchName, depName := range conf.Switches {
+		sw, err := toggleswitch.FromDependencies(deps, depName)
+		if err != nil {
+			return nil, fmt.Errorf("getting switch %q: %w", switchName, err)
+		}
+		switches[switchName] = sw
+	}
+
+	prog, err := compileProgram(conf)
+	if err != nil {
+		return nil, fmt.Errorf("compiling cycle program: %w", err)
+	}
+	available := make(map[string]bool, len(switches))
+	for switchName := range switches {
+		available[switchName] = true
+	}
+	if err := prog.ValidateSwitches(available); err != nil {
+		return nil, err
+	}
+
+	var store *trialStore
+	if conf.TrialLogPath != "" {
+		store, err = newTrialStore(conf.TrialLogPath, logger)
+		if err != nil {
+			return nil, fmt.Errorf("opening trial_log_path: %w", err)
+		}
+	}
+
+	events := newEventBroadcaster()
+	ring := newRingEventSink(name.Name, defaultEventRingCap, metrics.kettleEventsDropped)
+	events.addSink(ring)
+
+	var eventFile *eventFileSink
+	if conf.EventLogPath != "" {
+		eventFile, err = newEventFileSink(conf.EventLogPath, logger)
+		if err != nil {
+			return nil, fmt.Errorf("opening event_log_path: %w", err)
+		}
+		events.addSink(eventFile)
+	}
+	if conf.WebhookURL != "" {
+		events.addSink(newWebhookEventSink(conf.WebhookURL, logger))
+	}
+
+	var stateStore TrialStateStore
+	if conf.TrialStatePath != "" {
+		stateStore = newJSONFileTrialStateStore(conf.TrialStatePath, name.Name, logger)
+	}
+
+	var leaseHandle lease.Lease
+	var leaseRenewInterval time.Duration
+	if conf.Lease != nil {
+		conf.Lease.ApplyDefaults()
+		if err := conf.Lease.Validate("lease"); err != nil {
+			return nil, err
+		}
+		leaseHandle, err = lease.New(*conf.Lease, name.Name, logger)
+		if err != nil {
+			return nil, fmt.Errorf("building lease: %w", err)
+		}
+		leaseRenewInterval = conf.Lease.RenewInterval()
+	}
+
+	applyPacingDefaults(conf)
+
 	cancelCtx, cancelFunc := context.WithCancel(context.Background())
 
 	s := &kettleCycleTestController{
-		name:        name,
-		logger:      logger,
-		cfg:         conf,
-		arm:         a,
-		resting:     resting,
-		pourPrep:    pourPrep,
-		forceSensor: fs,
-		cancelCtx:   cancelCtx,
-		cancelFunc:  cancelFunc,
+		name:               name,
+		logger:             logger,
+		cfg:                conf,
+		arm:                a,
+		resting:            resting,
+		pourPrep:           pourPrep,
+		forceSensor:        fs,
+		switches:           switches,
+		program:            prog,
+		cancelCtx:          cancelCtx,
+		cancelFunc:         cancelFunc,
+		trialStore:         store,
+		trialStateStore:    stateStore,
+		registry:           options.registry,
+		metrics:            metrics,
+		metricsServer:      startControllerMetricsServer(conf.MetricsAddr, options.registry, logger),
+		events:             events,
+		eventRing:          ring,
+		eventFile:          eventFile,
+		clk:                realClock{},
+		lease:              leaseHandle,
+		leaseRenewInterval: leaseRenewInterval,
+		leaseHeld:          leaseHandle == nil,
 	}
+
+	s.rehydrateTrial()
+
 	return s, nil
 }
 
+// rehydrateTrial loads any persisted trial from trialStateStore and, if it
+// hadn't finished, restores it as activeTrial so a crash or restart doesn't
+// lose a trial already in progress. A degraded or lost-leadership trial is
+// restored but left for handleResume to restart, matching handleResume's own
+// contract. A persisted trial whose max_duration_ms already elapsed while the
+// controller was down is treated as expired rather than resumed. If a lease
+// is configured, it must be re-acquired before cycleLoop restarts; failing
+// to get it leaves the trial as lost-leadership for handleResume to retry.
+func (s *kettleCycleTestController) rehydrateTrial() {
+	if s.trialStateStore == nil {
+		return
+	}
+	p, err := s.trialStateStore.Load()
+	if err != nil {
+		s.logger.Warnf("trial state store: load failed: %v", err)
+		return
+	}
+	if p == nil || p.TerminalState != "" {
+		return
+	}
+
+	maxDuration := time.Duration(p.MaxDurationMs) * time.Millisecond
+	if maxDuration > 0 {
+		maxDuration -= time.Since(p.StartedAt)
+		if maxDuration <= 0 {
+			s.logger.Warnf("trial %s: max_duration_ms elapsed while controller was down, not resuming", p.TrialID)
+			if err := s.trialStateStore.Clear(); err != nil {
+				s.logger.Warnf("trial state store: clear failed: %v", err)
+			}
+			return
+		}
+	}
+
+	trial := &trialState{
+		trialID:             p.TrialID,
+		startedAt:           p.StartedAt,
+		lastCycleAt:         p.LastCycleAt,
+		cycleCount:          p.CycleCount,
+		stopCh:              make(chan struct{}),
+		targetCycles:        p.TargetCycles,
+		intervalMs:          p.IntervalMs,
+		maxDuration:         maxDuration,
+		abortOnError:        p.AbortOnError,
+		currentCycle:        p.CurrentCycle,
+		completedCycles:     p.CompletedCycles,
+		failedCycles:        p.FailedCycles,
+		consecutiveFailures: p.ConsecutiveFailures,
+		degraded:            p.Degraded,
+		lostLeadership:      p.LostLeadership,
+	}
+	s.activeTrial = trial
+
+	s.logger.Infof("resuming trial %s from persisted state (completed=%d failed=%d degraded=%v lost_leadership=%v)",
+		trial.trialID, trial.completedCycles, trial.failedCycles, trial.degraded, trial.lostLeadership)
+	s.events.Publish("trial_resumed", map[string]interface{}{
+		"controller":      s.name.Name,
+		"trial_id":        trial.trialID,
+		"degraded":        trial.degraded,
+		"lost_leadership": trial.lostLeadership,
+	})
+
+	if trial.degraded || trial.lostLeadership {
+		return
+	}
+
+	if s.lease != nil {
+		if err := s.lease.Acquire(s.cancelCtx); err != nil {
+			s.logger.Warnf("trial %s: lease acquisition failed on restart, leaving for handleResume: %v", trial.trialID, err)
+			s.loseLeadership(trial)
+			return
+		}
+		s.mu.Lock()
+		s.leaseHeld = true
+		s.mu.Unlock()
+	}
+
+	s.metrics.kettleTrialActive.WithLabelValues(s.name.Name).Set(1)
+	go s.cycleLoop(trial)
+}
+
+// persistTrial writes trial's current state to trialStateStore, if
+// configured; save failures are logged, not returned, matching
+// trialStore/eventFileSink's best-effort write handling elsewhere in this
+// package.
+func (s *kettleCycleTestController) persistTrial(trial *trialState) {
+	if s.trialStateStore == nil {
+		return
+	}
+
+	s.mu.Lock()
+	p := &persistedTrial{
+		TrialID:             trial.trialID,
+		StartedAt:           trial.startedAt,
+		LastCycleAt:         trial.lastCycleAt,
+		CycleCount:          trial.cycleCount,
+		TargetCycles:        trial.targetCycles,
+		IntervalMs:          trial.intervalMs,
+		MaxDurationMs:       trial.maxDuration.Milliseconds(),
+		AbortOnError:        trial.abortOnError,
+		CurrentCycle:        trial.currentCycle,
+		CompletedCycles:     trial.completedCycles,
+		FailedCycles:        trial.failedCycles,
+		ConsecutiveFailures: trial.consecutiveFailures,
+		Degraded:            trial.degraded,
+		LostLeadership:      trial.lostLeadership,
+		TerminalState:       trial.terminalState,
+	}
+	s.mu.Unlock()
+
+	if err := s.trialStateStore.Save(p); err != nil {
+		s.logger.Warnf("trial state store: save failed: %v", err)
+	}
+}
+
+// clearPersistedTrial wipes trialStateStore, if configured.
+func (s *kettleCycleTestController) clearPersistedTrial() {
+	if s.trialStateStore == nil {
+		return
+	}
+	if err := s.trialStateStore.Clear(); err != nil {
+		s.logger.Warnf("trial state store: clear failed: %v", err)
+	}
+}
+
+// applyPacingDefaults fills in zero-valued pacing fields on conf with their
+// defaults, in place.
+func applyPacingDefaults(conf *Config) {
+	if conf.MinCycleIntervalMs <= 0 {
+		conf.MinCycleIntervalMs = defaultMinCycleIntervalMs
+	}
+	if conf.MaxCycleIntervalMs <= 0 {
+		conf.MaxCycleIntervalMs = defaultMaxCycleIntervalMs
+	}
+	if conf.FailureBackoffBaseMs <= 0 {
+		conf.FailureBackoffBaseMs = defaultFailureBackoffBaseMs
+	}
+	if conf.FailureBackoffMaxMs <= 0 {
+		conf.FailureBackoffMaxMs = defaultFailureBackoffMaxMs
+	}
+	if conf.ConsecutiveFailureLimit <= 0 {
+		conf.ConsecutiveFailureLimit = defaultConsecutiveFailureLimit
+	}
+}
+
+// compileProgram loads and parses the cycle program handleExecuteCycle will
+// run: from conf.ProgramPath if set, else conf.Program inline, else the
+// historical pour_prep->resting sequence via program.Default().
+func compileProgram(conf *Config) (*program.Program, error) {
+	switch {
+	case conf.ProgramPath != "":
+		data, err := os.ReadFile(conf.ProgramPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading program_path: %w", err)
+		}
+		return program.Parse(data)
+	case conf.Program != "":
+		return program.Parse([]byte(conf.Program))
+	default:
+		return program.Default(), nil
+	}
+}
+
 func (s *kettleCycleTestController) Name() resource.Name {
 	return s.name
 }
@@ -140,66 +556,72 @@ func (s *kettleCycleTestController) DoCommand(ctx context.Context, cmd map[strin
 	case "execute_cycle":
 		return s.handleExecuteCycle(ctx)
 	case "start":
-		return s.handleStart()
+		return s.handleStart(ctx, cmd)
 	case "stop":
 		return s.handleStop()
+	case "resume":
+		return s.handleResume(ctx)
+	case "abandon_trial":
+		return s.handleAbandonTrial()
 	case "status":
 		return s.handleStatus()
+	case "list_trials":
+		return s.handleListTrials(cmd)
+	case "get_trial":
+		return s.handleGetTrial(cmd)
+	case "export_trial":
+		return s.handleExportTrial(cmd)
+	case "metrics":
+		return s.handleMetrics()
+	case "events":
+		return s.handleEvents(cmd)
 	default:
 		return nil, fmt.Errorf("unknown command: %s", command)
 	}
 }
 
-func (s *kettleCycleTestController) handleExecuteCycle(ctx context.Context) (map[string]interface{}, error) {
-	if err := s.pourPrep.SetPosition(ctx, 2, nil); err != nil {
-		return nil, fmt.Errorf("moving to pour_prep position: %w", err)
+func (s *kettleCycleTestController) handleExecuteCycle(ctx context.Context) (result map[string]interface{}, err error) {
+	start := time.Now()
+
+	s.mu.Lock()
+	trialID := ""
+	if s.activeTrial != nil {
+		trialID = s.activeTrial.trialID
 	}
+	leaseHeld := s.leaseHeld
+	s.mu.Unlock()
 
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case <-time.After(1 * time.Second):
+	if !leaseHeld {
+		return nil, fmt.Errorf("lease not held: refusing to drive the arm")
 	}
 
-	// Start force capture if sensor is configured
-	if s.forceSensor != nil {
-		s.mu.Lock()
-		captureCmd := map[string]interface{}{"command": "start_capture"}
-		if s.activeTrial != nil {
-			captureCmd["trial_id"] = s.activeTrial.trialID
-			captureCmd["cycle_count"] = s.activeTrial.cycleCount
-		}
-		s.mu.Unlock()
+	s.events.Publish("cycle_started", map[string]interface{}{"controller": s.name.Name, "trial_id": trialID})
 
-		_, err := s.forceSensor.DoCommand(ctx, captureCmd)
+	defer func() {
+		resultLabel := "success"
+		eventType := "cycle_completed"
 		if err != nil {
-			s.logger.Warnf("failed to start force capture: %v", err)
+			resultLabel = "failure"
+			eventType = "cycle_failed"
 		}
-	}
+		s.metrics.kettleCyclesTotal.WithLabelValues(s.name.Name, resultLabel).Inc()
+		s.metrics.kettleCycleDuration.WithLabelValues(s.name.Name).Observe(time.Since(start).Seconds())
 
-	if err := s.resting.SetPosition(ctx, 2, nil); err != nil {
-		// Try to end capture on error
-		if s.forceSensor != nil {
-			s.forceSensor.DoCommand(ctx, map[string]interface{}{"command": "end_capture"})
+		fields := map[string]interface{}{
+			"controller":  s.name.Name,
+			"trial_id":    trialID,
+			"duration_ms": time.Since(start).Milliseconds(),
 		}
-		return nil, fmt.Errorf("returning to resting position: %w", err)
-	}
-
-	// Wait for arm to stop moving
-	if err := s.waitForArmStopped(ctx); err != nil {
-		s.logger.Warnf("error waiting for arm to stop: %v", err)
-	}
-
-	// End force capture
-	var captureResult map[string]interface{}
-	if s.forceSensor != nil {
-		var err error
-		captureResult, err = s.forceSensor.DoCommand(ctx, map[string]interface{}{"command": "end_capture"})
 		if err != nil {
-			s.logger.Warnf("failed to end force capture: %v", err)
-		} else {
-			s.logger.Infof("force capture: %v", captureResult)
+			fields["error"] = err.Error()
 		}
+		s.events.Publish(eventType, fields)
+	}()
+
+	var captures map[string]map[string]interface{}
+	captures, err = s.runProgram(ctx, trialID)
+	if err != nil {
+		return nil, err
 	}
 
 	s.mu.Lock()
@@ -209,29 +631,41 @@ func (s *kettleCycleTestController) handleExecuteCycle(ctx context.Context) (map
 	}
 	s.mu.Unlock()
 
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case <-time.After(1 * time.Second):
-	}
-
-	result := map[string]interface{}{"status": "completed"}
-	if captureResult != nil {
-		result["force_capture"] = captureResult
+	result = map[string]interface{}{"status": "completed"}
+	if len(captures) > 0 {
+		capturesOut := make(map[string]interface{}, len(captures))
+		for name, c := range captures {
+			capturesOut[name] = c
+		}
+		result["captures"] = capturesOut
+		// "capture" is the variable name program.Default() binds its
+		// end_force_capture to; surface it under the pre-program response
+		// key too so existing callers of the default program keep working.
+		if c, ok := captures["capture"]; ok {
+			result["force_capture"] = c
+		}
 	}
 	return result, nil
 }
 
-func (s *kettleCycleTestController) waitForArmStopped(ctx context.Context) error {
+// waitForArmStopped polls the arm until it reports not moving or timeout
+// elapses.
+func (s *kettleCycleTestController) waitForArmStopped(ctx context.Context, timeout time.Duration) error {
+	start := time.Now()
+	defer func() {
+		s.metrics.kettleArmWaitSeconds.WithLabelValues(s.name.Name).Observe(time.Since(start).Seconds())
+	}()
+
 	ticker := time.NewTicker(50 * time.Millisecond)
 	defer ticker.Stop()
 
-	timeout := time.After(10 * time.Second)
+	deadline := time.After(timeout)
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-timeout:
+		case <-deadline:
+			s.events.Publish("arm_stall", map[string]interface{}{"controller": s.name.Name})
 			return fmt.Errorf("timeout waiting for arm to stop")
 		case <-ticker.C:
 			moving, err := s.arm.IsMoving(ctx)
@@ -245,43 +679,405 @@ func (s *kettleCycleTestController) waitForArmStopped(ctx context.Context) error
 	}
 }
 
-func (s *kettleCycleTestController) handleStart() (map[string]interface{}, error) {
+func (s *kettleCycleTestController) handleStart(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	if s.activeTrial != nil {
+		trialID := s.activeTrial.trialID
+		s.mu.Unlock()
+		return nil, fmt.Errorf("trial already running: %s", trialID)
+	}
+	s.mu.Unlock()
+
+	if s.lease != nil {
+		if err := s.lease.Acquire(ctx); err != nil {
+			return nil, fmt.Errorf("acquiring lease: %w", err)
+		}
+	}
 
+	s.mu.Lock()
 	if s.activeTrial != nil {
-		return nil, fmt.Errorf("trial already running: %s", s.activeTrial.trialID)
+		trialID := s.activeTrial.trialID
+		s.mu.Unlock()
+		if s.lease != nil {
+			s.lease.Release(ctx)
+		}
+		return nil, fmt.Errorf("trial already running: %s", trialID)
 	}
 
 	now := time.Now()
 	trialID := fmt.Sprintf("trial-%s", now.Format("20060102-150405"))
-	stopCh := make(chan struct{})
 
-	s.activeTrial = &trialState{
-		trialID:   trialID,
-		startedAt: now,
-		stopCh:    stopCh,
+	trial := &trialState{
+		trialID:      trialID,
+		startedAt:    now,
+		stopCh:       make(chan struct{}),
+		targetCycles: intFromCmd(cmd, "cycles", 0),
+		intervalMs:   intFromCmd(cmd, "interval_ms", 0),
+		maxDuration:  time.Duration(intFromCmd(cmd, "max_duration_ms", 0)) * time.Millisecond,
+		abortOnError: boolFromCmd(cmd, "abort_on_error", false),
+	}
+	s.activeTrial = trial
+	s.leaseHeld = true
+	s.mu.Unlock()
+
+	if s.trialStore != nil {
+		s.trialStore.AppendEvent(map[string]interface{}{
+			"event":           "start",
+			"trial_id":        trialID,
+			"time":            now.Format(time.RFC3339Nano),
+			"target_cycles":   trial.targetCycles,
+			"interval_ms":     trial.intervalMs,
+			"max_duration_ms": trial.maxDuration.Milliseconds(),
+			"abort_on_error":  trial.abortOnError,
+		})
 	}
 
+	s.events.Publish("trial_started", map[string]interface{}{
+		"controller":      s.name.Name,
+		"trial_id":        trialID,
+		"target_cycles":   trial.targetCycles,
+		"interval_ms":     trial.intervalMs,
+		"max_duration_ms": trial.maxDuration.Milliseconds(),
+		"abort_on_error":  trial.abortOnError,
+	})
+
+	s.metrics.kettleTrialActive.WithLabelValues(s.name.Name).Set(1)
+	s.persistTrial(trial)
+
 	// Start background cycling loop
-	go s.cycleLoop(stopCh)
+	go s.cycleLoop(trial)
 
 	return map[string]interface{}{
 		"trial_id": trialID,
 	}, nil
 }
 
-func (s *kettleCycleTestController) cycleLoop(stopCh chan struct{}) {
+// handleResume clears a degraded or lost-leadership trial's failure streak
+// and restarts cycleLoop, picking up from the same cycle counts rather than
+// starting a new trial_id. If a lease is configured, it must be re-acquired
+// (possibly held by this same instance from before a restart, or contended
+// by another) before the trial resumes cycling.
+func (s *kettleCycleTestController) handleResume(ctx context.Context) (map[string]interface{}, error) {
+	s.mu.Lock()
+	if s.activeTrial == nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("no active trial to resume")
+	}
+	trial := s.activeTrial
+	if !trial.degraded && !trial.lostLeadership {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("trial %s is not degraded or lost_leadership", trial.trialID)
+	}
+	s.mu.Unlock()
+
+	if s.lease != nil {
+		if err := s.lease.Acquire(ctx); err != nil {
+			return nil, fmt.Errorf("acquiring lease: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	trial.degraded = false
+	trial.lostLeadership = false
+	trial.consecutiveFailures = 0
+	s.leaseHeld = true
+	s.mu.Unlock()
+
+	s.metrics.kettleTrialActive.WithLabelValues(s.name.Name).Set(1)
+	s.events.Publish("trial_resumed", map[string]interface{}{
+		"controller": s.name.Name,
+		"trial_id":   trial.trialID,
+	})
+	s.persistTrial(trial)
+
+	go s.cycleLoop(trial)
+
+	return map[string]interface{}{
+		"trial_id": trial.trialID,
+	}, nil
+}
+
+// handleAbandonTrial forgets the active trial, if any, and wipes persisted
+// state, for when the underlying hardware is irrecoverable and
+// handleStop/handleResume's normal shutdown/retry semantics no longer apply.
+func (s *kettleCycleTestController) handleAbandonTrial() (map[string]interface{}, error) {
+	s.mu.Lock()
+	trialID := ""
+	if s.activeTrial != nil {
+		trialID = s.activeTrial.trialID
+		if s.activeTrial.terminalState == "" && !s.activeTrial.degraded && !s.activeTrial.lostLeadership {
+			close(s.activeTrial.stopCh)
+		}
+	}
+	s.activeTrial = nil
+	s.mu.Unlock()
+
+	s.metrics.kettleTrialActive.WithLabelValues(s.name.Name).Set(0)
+	s.clearPersistedTrial()
+
+	s.events.Publish("trial_abandoned", map[string]interface{}{
+		"controller": s.name.Name,
+		"trial_id":   trialID,
+	})
+
+	return map[string]interface{}{"trial_id": trialID}, nil
+}
+
+// intFromCmd extracts an integer field from a DoCommand map, which may decode
+// JSON numbers as float64 or, in tests, as plain int.
+func intFromCmd(cmd map[string]interface{}, key string, def int) int {
+	switch v := cmd[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return def
+	}
+}
+
+func boolFromCmd(cmd map[string]interface{}, key string, def bool) bool {
+	if v, ok := cmd[key].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// cycleLoop drives a scheduled run of trial.targetCycles cycles (0 =
+// unbounded), capping total wall-clock time at trial.maxDuration (0 = no
+// cap). Pacing comes from successInterval after a successful cycle and
+// backoffDuration after a failed one; once trial.consecutiveFailures reaches
+// Config.ConsecutiveFailureLimit the trial goes degraded and the loop stops
+// without clearing it (see degradeTrial). If a lease is configured, it's
+// renewed on s.leaseRenewInterval; a failed renewal means leadership was
+// lost, so the loop stops via loseLeadership instead of driving the arm any
+// further. It otherwise terminates promptly on an explicit stop, module
+// Close, or a terminal trial outcome; a stop or terminal outcome leaves
+// trial.terminalState set so handleStop/GetState can read it back even if
+// the trial finished on its own, while module Close returns without setting
+// it. Whatever the exit path, the lease (if any) is released on the way
+// out so a stuck trial never holds leadership forever.
+func (s *kettleCycleTestController) cycleLoop(trial *trialState) {
+	ctx := s.cancelCtx
+	if trial.maxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(s.cancelCtx, trial.maxDuration)
+		defer cancel()
+	}
+
+	if s.lease != nil {
+		defer func() {
+			s.mu.Lock()
+			s.leaseHeld = false
+			s.mu.Unlock()
+			if err := s.lease.Release(s.cancelCtx); err != nil {
+				s.logger.Warnf("trial %s: lease release failed: %v", trial.trialID, err)
+			}
+		}()
+	}
+	lastLeaseRenewal := s.clk.Now()
+
 	for {
 		select {
-		case <-stopCh:
+		case <-trial.stopCh:
+			s.finishTrial(trial, "aborted")
 			return
-		case <-s.cancelCtx.Done():
+		case <-ctx.Done():
+			if s.cancelCtx.Err() != nil {
+				return // module is closing
+			}
+			s.logger.Warnf("trial %s stopped: max_duration_ms elapsed", trial.trialID)
+			s.finishTrial(trial, "aborted")
 			return
 		default:
-			s.handleExecuteCycle(s.cancelCtx)
 		}
+
+		if s.lease != nil && s.clk.Now().Sub(lastLeaseRenewal) >= s.leaseRenewInterval {
+			if err := s.lease.Renew(ctx); err != nil {
+				s.logger.Warnf("trial %s: lease renewal failed: %v", trial.trialID, err)
+				s.loseLeadership(trial)
+				return
+			}
+			lastLeaseRenewal = s.clk.Now()
+		}
+
+		s.mu.Lock()
+		cycleIndex := trial.currentCycle
+		trial.currentCycle++
+		s.mu.Unlock()
+
+		start := time.Now()
+		_, err := s.handleExecuteCycle(ctx)
+		duration := time.Since(start)
+
+		s.mu.Lock()
+		trial.lastCycleDurationMs = duration.Milliseconds()
+		errStr := ""
+		if err != nil {
+			errStr = err.Error()
+			trial.failedCycles++
+			trial.consecutiveFailures++
+		} else {
+			trial.completedCycles++
+			trial.consecutiveFailures = 0
+		}
+		trial.pushRecord(cycleRecord{index: cycleIndex, startedAt: start, durationMs: duration.Milliseconds(), err: errStr})
+
+		reachedTarget := trial.targetCycles > 0 && trial.completedCycles+trial.failedCycles >= trial.targetCycles
+		abort := err != nil && trial.abortOnError
+		degrade := err != nil && !abort && trial.consecutiveFailures >= s.cfg.ConsecutiveFailureLimit
+
+		pacing := s.successInterval(trial)
+		if err != nil {
+			pacing = backoffDuration(
+				time.Duration(s.cfg.FailureBackoffBaseMs)*time.Millisecond,
+				time.Duration(s.cfg.FailureBackoffMaxMs)*time.Millisecond,
+				trial.consecutiveFailures,
+			)
+		}
+		trial.nextCycleAt = s.clk.Now().Add(pacing)
+		s.mu.Unlock()
+
+		if s.trialStore != nil {
+			cycleEvent := "cycle_completed"
+			if err != nil {
+				cycleEvent = "cycle_failed"
+			}
+			record := map[string]interface{}{
+				"event":       cycleEvent,
+				"trial_id":    trial.trialID,
+				"time":        time.Now().Format(time.RFC3339Nano),
+				"cycle_index": cycleIndex,
+				"duration_ms": duration.Milliseconds(),
+			}
+			if err != nil {
+				record["error"] = errStr
+			}
+			s.trialStore.AppendEvent(record)
+		}
+		s.persistTrial(trial)
+
+		if abort {
+			s.finishTrial(trial, "failed")
+			return
+		}
+		if reachedTarget {
+			s.finishTrial(trial, "completed")
+			return
+		}
+		if degrade {
+			s.degradeTrial(trial)
+			return
+		}
+
+		select {
+		case <-trial.stopCh:
+			s.finishTrial(trial, "aborted")
+			return
+		case <-ctx.Done():
+			continue // let the top-of-loop select classify module-close vs. max_duration_ms
+		case <-s.clk.After(pacing):
+		}
+	}
+}
+
+// successInterval is the dwell after a successful cycle: the trial's own
+// interval_ms if it set one, clamped to [MinCycleIntervalMs,
+// MaxCycleIntervalMs], otherwise MinCycleIntervalMs. Callers must hold s.mu.
+func (s *kettleCycleTestController) successInterval(trial *trialState) time.Duration {
+	min := time.Duration(s.cfg.MinCycleIntervalMs) * time.Millisecond
+	max := time.Duration(s.cfg.MaxCycleIntervalMs) * time.Millisecond
+	if trial.intervalMs <= 0 {
+		return min
+	}
+	interval := time.Duration(trial.intervalMs) * time.Millisecond
+	switch {
+	case interval < min:
+		return min
+	case interval > max:
+		return max
+	default:
+		return interval
+	}
+}
+
+// degradeTrial marks trial degraded after ConsecutiveFailureLimit consecutive
+// failures and stops cycleLoop without clearing s.activeTrial, so
+// handleStatus keeps reporting the failing trial_id until handleResume
+// restarts it.
+func (s *kettleCycleTestController) degradeTrial(trial *trialState) {
+	s.mu.Lock()
+	trial.degraded = true
+	consecutiveFailures := trial.consecutiveFailures
+	s.mu.Unlock()
+
+	s.metrics.kettleTrialActive.WithLabelValues(s.name.Name).Set(0)
+	s.logger.Warnf("trial %s degraded after %d consecutive failures", trial.trialID, consecutiveFailures)
+	s.events.Publish("trial_degraded", map[string]interface{}{
+		"controller":           s.name.Name,
+		"trial_id":             trial.trialID,
+		"consecutive_failures": consecutiveFailures,
+	})
+	s.persistTrial(trial)
+}
+
+// loseLeadership marks trial lost_leadership after a failed lease renewal
+// and stops cycleLoop without clearing s.activeTrial, mirroring
+// degradeTrial: handleStatus keeps reporting the trial until handleResume
+// re-acquires the lease and restarts it.
+func (s *kettleCycleTestController) loseLeadership(trial *trialState) {
+	s.mu.Lock()
+	trial.lostLeadership = true
+	s.leaseHeld = false
+	s.mu.Unlock()
+
+	s.metrics.kettleTrialActive.WithLabelValues(s.name.Name).Set(0)
+	s.logger.Warnf("trial %s lost leadership", trial.trialID)
+	s.events.Publish("trial_lost_leadership", map[string]interface{}{
+		"controller": s.name.Name,
+		"trial_id":   trial.trialID,
+	})
+	s.persistTrial(trial)
+}
+
+// finishTrial marks a trial terminal without clearing s.activeTrial, so a
+// subsequent handleStatus or handleStop still sees the final cycle counts
+// instead of racing an "idle" reset.
+func (s *kettleCycleTestController) finishTrial(trial *trialState, state string) {
+	s.mu.Lock()
+	alreadyTerminal := trial.terminalState != ""
+	if !alreadyTerminal {
+		trial.terminalState = state
+	}
+	completedCycles := trial.completedCycles
+	failedCycles := trial.failedCycles
+	trialID := trial.trialID
+	s.mu.Unlock()
+
+	if alreadyTerminal {
+		return
 	}
+	s.metrics.kettleTrialActive.WithLabelValues(s.name.Name).Set(0)
+
+	s.events.Publish("trial_stopped", map[string]interface{}{
+		"controller":       s.name.Name,
+		"trial_id":         trialID,
+		"state":            state,
+		"completed_cycles": completedCycles,
+		"failed_cycles":    failedCycles,
+	})
+	s.clearPersistedTrial()
+
+	if s.trialStore == nil {
+		return
+	}
+	s.trialStore.AppendEvent(map[string]interface{}{
+		"event":            state,
+		"trial_id":         trialID,
+		"time":             time.Now().Format(time.RFC3339Nano),
+		"completed_cycles": completedCycles,
+		"failed_cycles":    failedCycles,
+	})
 }
 
 func (s *kettleCycleTestController) handleStop() (map[string]interface{}, error) {
@@ -292,15 +1088,43 @@ func (s *kettleCycleTestController) handleStop() (map[string]interface{}, error)
 		return nil, fmt.Errorf("no active trial to stop")
 	}
 
-	// Signal the loop to stop
-	close(s.activeTrial.stopCh)
+	trial := s.activeTrial
+	operatorStopped := trial.terminalState == ""
+	if operatorStopped {
+		close(trial.stopCh)
+		trial.terminalState = "aborted"
+		s.metrics.kettleTrialActive.WithLabelValues(s.name.Name).Set(0)
+	}
 
 	result := map[string]interface{}{
-		"trial_id":    s.activeTrial.trialID,
-		"cycle_count": s.activeTrial.cycleCount,
+		"trial_id":         trial.trialID,
+		"cycle_count":      trial.cycleCount,
+		"completed_cycles": trial.completedCycles,
+		"failed_cycles":    trial.failedCycles,
+		"state":            trial.terminalState,
 	}
 	s.activeTrial = nil
 
+	if operatorStopped {
+		s.events.Publish("trial_stopped", map[string]interface{}{
+			"controller":       s.name.Name,
+			"trial_id":         trial.trialID,
+			"state":            trial.terminalState,
+			"completed_cycles": trial.completedCycles,
+			"failed_cycles":    trial.failedCycles,
+		})
+		s.clearPersistedTrial()
+		if s.trialStore != nil {
+			s.trialStore.AppendEvent(map[string]interface{}{
+				"event":            "stop",
+				"trial_id":         trial.trialID,
+				"time":             time.Now().Format(time.RFC3339Nano),
+				"completed_cycles": trial.completedCycles,
+				"failed_cycles":    trial.failedCycles,
+			})
+		}
+	}
+
 	return result, nil
 }
 
@@ -309,6 +1133,20 @@ func (s *kettleCycleTestController) handleStatus() (map[string]interface{}, erro
 }
 
 func (s *kettleCycleTestController) GetState() map[string]interface{} {
+	state := s.getStateLocked()
+
+	if s.trialStore != nil {
+		if summary, err := s.trialStore.LastTrialSummary(); err != nil {
+			s.logger.Warnf("reading last trial summary: %v", err)
+		} else if summary != nil {
+			state["last_trial_summary"] = summary
+		}
+	}
+
+	return state
+}
+
+func (s *kettleCycleTestController) getStateLocked() map[string]interface{} {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -327,16 +1165,165 @@ func (s *kettleCycleTestController) GetState() map[string]interface{} {
 		lastCycleAt = s.activeTrial.lastCycleAt.Format(time.RFC3339)
 	}
 
+	state := "running"
+	if s.activeTrial.terminalState != "" {
+		state = s.activeTrial.terminalState
+	}
+	if s.activeTrial.degraded {
+		state = "degraded"
+	}
+	if s.activeTrial.lostLeadership {
+		state = "lost_leadership"
+	}
+
+	nextCycleAt := ""
+	if !s.activeTrial.nextCycleAt.IsZero() {
+		nextCycleAt = s.activeTrial.nextCycleAt.Format(time.RFC3339)
+	}
+
+	return map[string]interface{}{
+		"state":                  state,
+		"trial_id":               s.activeTrial.trialID,
+		"cycle_count":            s.activeTrial.cycleCount,
+		"last_cycle_at":          lastCycleAt,
+		"should_sync":            true,
+		"current_cycle":          s.activeTrial.currentCycle,
+		"completed_cycles":       s.activeTrial.completedCycles,
+		"failed_cycles":          s.activeTrial.failedCycles,
+		"last_cycle_duration_ms": s.activeTrial.lastCycleDurationMs,
+		"next_cycle_at":          nextCycleAt,
+		"recent_cycles":          cycleRecordsToList(s.activeTrial.records),
+	}
+}
+
+// cycleRecordsToList renders a trial's ring buffer of recent cycle outcomes
+// (oldest first) for inclusion in handleStatus/GetState output.
+func cycleRecordsToList(records []cycleRecord) []interface{} {
+	list := make([]interface{}, len(records))
+	for i, r := range records {
+		list[i] = map[string]interface{}{
+			"index":       r.index,
+			"started_at":  r.startedAt.Format(time.RFC3339),
+			"duration_ms": r.durationMs,
+			"error":       r.err,
+		}
+	}
+	return list
+}
+
+// handleListTrials returns summaries of the last limit trials (oldest first
+// within that window), or every trial if limit is unset/zero.
+func (s *kettleCycleTestController) handleListTrials(cmd map[string]interface{}) (map[string]interface{}, error) {
+	if s.trialStore == nil {
+		return nil, fmt.Errorf("trial_log_path not configured")
+	}
+	limit := intFromCmd(cmd, "limit", 0)
+	trials, err := s.trialStore.ListTrials(limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing trials: %w", err)
+	}
+	list := make([]interface{}, len(trials))
+	for i, t := range trials {
+		list[i] = t
+	}
+	return map[string]interface{}{"trials": list}, nil
+}
+
+func (s *kettleCycleTestController) handleGetTrial(cmd map[string]interface{}) (map[string]interface{}, error) {
+	if s.trialStore == nil {
+		return nil, fmt.Errorf("trial_log_path not configured")
+	}
+	trialID, ok := cmd["trial_id"].(string)
+	if !ok || trialID == "" {
+		return nil, fmt.Errorf("missing or invalid 'trial_id' field")
+	}
+	events, err := s.trialStore.GetTrial(trialID)
+	if err != nil {
+		return nil, fmt.Errorf("reading trial %q: %w", trialID, err)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no recorded events for trial %q", trialID)
+	}
+	list := make([]interface{}, len(events))
+	for i, e := range events {
+		list[i] = e
+	}
+	return map[string]interface{}{"trial_id": trialID, "events": list}, nil
+}
+
+func (s *kettleCycleTestController) handleExportTrial(cmd map[string]interface{}) (map[string]interface{}, error) {
+	if s.trialStore == nil {
+		return nil, fmt.Errorf("trial_log_path not configured")
+	}
+	trialID, ok := cmd["trial_id"].(string)
+	if !ok || trialID == "" {
+		return nil, fmt.Errorf("missing or invalid 'trial_id' field")
+	}
+	agg, err := s.trialStore.ExportTrial(trialID)
+	if err != nil {
+		return nil, fmt.Errorf("exporting trial %q: %w", trialID, err)
+	}
+	if agg.TotalCycles == 0 {
+		return nil, fmt.Errorf("no recorded cycles for trial %q", trialID)
+	}
 	return map[string]interface{}{
-		"state":         "running",
-		"trial_id":      s.activeTrial.trialID,
-		"cycle_count":   s.activeTrial.cycleCount,
-		"last_cycle_at": lastCycleAt,
-		"should_sync":   true,
+		"trial_id":                 trialID,
+		"total_cycles":             agg.TotalCycles,
+		"failed_cycles":            agg.FailedCycles,
+		"mean_cycle_duration_ms":   agg.MeanCycleDurationMs,
+		"median_cycle_duration_ms": agg.MedianCycleDurationMs,
+		"p95_cycle_duration_ms":    agg.P95CycleDurationMs,
+	}, nil
+}
+
+// handleMetrics renders s.registry (covering this controller and, in
+// production, any configured force sensors) as a text-format snapshot.
+func (s *kettleCycleTestController) handleMetrics() (map[string]interface{}, error) {
+	families, err := s.registry.Gather()
+	if err != nil {
+		return nil, fmt.Errorf("gathering metrics: %w", err)
 	}
+
+	var buf bytes.Buffer
+	encoder := expfmt.NewEncoder(&buf, expfmt.FmtText)
+	for _, mf := range families {
+		if err := encoder.Encode(mf); err != nil {
+			return nil, fmt.Errorf("encoding metrics: %w", err)
+		}
+	}
+
+	return map[string]interface{}{"metrics": buf.String()}, nil
 }
 
-func (s *kettleCycleTestController) Close(context.Context) error {
+// handleEvents returns lifecycle events with seq greater than "since",
+// oldest first, capped at "limit" (0 = no cap). Events only cover what the
+// in-memory ring still holds; older ones may have been dropped.
+func (s *kettleCycleTestController) handleEvents(cmd map[string]interface{}) (map[string]interface{}, error) {
+	since := int64(intFromCmd(cmd, "since", 0))
+	limit := intFromCmd(cmd, "limit", 0)
+	return map[string]interface{}{"events": s.eventRing.Since(since, limit)}, nil
+}
+
+func (s *kettleCycleTestController) Close(ctx context.Context) error {
 	s.cancelFunc()
+	s.metrics.kettleTrialActive.DeleteLabelValues(s.name.Name)
+
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Shutdown(ctx); err != nil {
+			s.logger.Warnf("metrics server shutdown: %v", err)
+		}
+	}
+
+	s.metrics.kettleEventsDropped.DeleteLabelValues(s.name.Name)
+	s.events.Flush()
+	if s.eventFile != nil {
+		if err := s.eventFile.Close(); err != nil {
+			s.logger.Warnf("event log close: %v", err)
+		}
+	}
+
+	if s.trialStore != nil {
+		return s.trialStore.Close()
+	}
 	return nil
 }
@@ -3,8 +3,14 @@ package kettlecycletest
 import (
 	"context"
 	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"go.viam.com/rdk/components/arm"
 	toggleswitch "go.viam.com/rdk/components/switch"
 	"go.viam.com/rdk/logging"
@@ -12,6 +18,22 @@ import (
 	"go.viam.com/rdk/testutils/inject"
 )
 
+// waitForTerminalState polls GetState until the trial reaches a terminal
+// state (anything but "running") or the timeout elapses.
+func waitForTerminalState(t *testing.T, kctrl *kettleCycleTestController, timeout time.Duration) map[string]interface{} {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		state := kctrl.GetState()
+		if state["state"] != "running" {
+			return state
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for trial to reach a terminal state")
+	return nil
+}
+
 func testDeps() (resource.Dependencies, *Config) {
 	cfg := &Config{
 		Arm:              "test-arm",
@@ -274,6 +296,167 @@ func TestTrialLifecycle(t *testing.T) {
 	})
 }
 
+func TestTrialStoreIntegration(t *testing.T) {
+	t.Run("trial lifecycle events are queryable via list_trials/get_trial/export_trial", func(t *testing.T) {
+		logger := logging.NewTestLogger(t)
+		name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+		deps, cfg := testDeps()
+		cfg.TrialLogPath = filepath.Join(t.TempDir(), "trials.jsonl")
+
+		ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+		if err != nil {
+			t.Fatalf("NewController failed: %v", err)
+		}
+		kctrl := ctrl.(*kettleCycleTestController)
+		defer kctrl.Close(context.Background())
+
+		startResult, err := kctrl.DoCommand(context.Background(), map[string]interface{}{
+			"command": "start",
+			"cycles":  float64(1),
+		})
+		if err != nil {
+			t.Fatalf("start failed: %v", err)
+		}
+		trialID := startResult["trial_id"].(string)
+
+		waitForTerminalState(t, kctrl, 5*time.Second)
+
+		listResult, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "list_trials"})
+		if err != nil {
+			t.Fatalf("list_trials failed: %v", err)
+		}
+		trials, ok := listResult["trials"].([]interface{})
+		if !ok || len(trials) != 1 {
+			t.Fatalf("expected 1 trial in list_trials, got %v", listResult["trials"])
+		}
+
+		getResult, err := kctrl.DoCommand(context.Background(), map[string]interface{}{
+			"command":  "get_trial",
+			"trial_id": trialID,
+		})
+		if err != nil {
+			t.Fatalf("get_trial failed: %v", err)
+		}
+		events, ok := getResult["events"].([]interface{})
+		if !ok || len(events) == 0 {
+			t.Fatalf("expected at least one event for %s, got %v", trialID, getResult["events"])
+		}
+
+		exportResult, err := kctrl.DoCommand(context.Background(), map[string]interface{}{
+			"command":  "export_trial",
+			"trial_id": trialID,
+		})
+		if err != nil {
+			t.Fatalf("export_trial failed: %v", err)
+		}
+		if exportResult["total_cycles"] != 1 {
+			t.Errorf("expected total_cycles=1, got %v", exportResult["total_cycles"])
+		}
+
+		status := kctrl.GetState()
+		summary, ok := status["last_trial_summary"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected last_trial_summary in status, got %v", status["last_trial_summary"])
+		}
+		if summary["trial_id"] != trialID {
+			t.Errorf("expected last_trial_summary.trial_id=%s, got %v", trialID, summary["trial_id"])
+		}
+	})
+
+	t.Run("trial history survives controller destroy and recreate", func(t *testing.T) {
+		logger := logging.NewTestLogger(t)
+		name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+		deps, cfg := testDeps()
+		cfg.TrialLogPath = filepath.Join(t.TempDir(), "trials.jsonl")
+
+		ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+		if err != nil {
+			t.Fatalf("NewController failed: %v", err)
+		}
+		kctrl := ctrl.(*kettleCycleTestController)
+
+		startResult, err := kctrl.DoCommand(context.Background(), map[string]interface{}{
+			"command": "start",
+			"cycles":  float64(1),
+		})
+		if err != nil {
+			t.Fatalf("start failed: %v", err)
+		}
+		trialID := startResult["trial_id"].(string)
+		waitForTerminalState(t, kctrl, 5*time.Second)
+
+		if err := kctrl.Close(context.Background()); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		// Recreate the controller against the same trial_log_path, as if the
+		// module had just restarted.
+		ctrl2, err := NewController(context.Background(), deps, name, cfg, logger)
+		if err != nil {
+			t.Fatalf("NewController (restart) failed: %v", err)
+		}
+		kctrl2 := ctrl2.(*kettleCycleTestController)
+		defer kctrl2.Close(context.Background())
+
+		getResult, err := kctrl2.DoCommand(context.Background(), map[string]interface{}{
+			"command":  "get_trial",
+			"trial_id": trialID,
+		})
+		if err != nil {
+			t.Fatalf("get_trial after restart failed: %v", err)
+		}
+		events, ok := getResult["events"].([]interface{})
+		if !ok || len(events) == 0 {
+			t.Fatalf("expected trial history to survive restart, got %v", getResult["events"])
+		}
+	})
+
+	t.Run("list_trials respects a limit", func(t *testing.T) {
+		logger := logging.NewTestLogger(t)
+		name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+		deps, cfg := testDeps()
+		cfg.TrialLogPath = filepath.Join(t.TempDir(), "trials.jsonl")
+
+		ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+		if err != nil {
+			t.Fatalf("NewController failed: %v", err)
+		}
+		kctrl := ctrl.(*kettleCycleTestController)
+		defer kctrl.Close(context.Background())
+
+		var trialIDs []string
+		for i := 0; i < 3; i++ {
+			startResult, err := kctrl.DoCommand(context.Background(), map[string]interface{}{
+				"command": "start",
+				"cycles":  float64(1),
+			})
+			if err != nil {
+				t.Fatalf("start failed: %v", err)
+			}
+			trialIDs = append(trialIDs, startResult["trial_id"].(string))
+			waitForTerminalState(t, kctrl, 5*time.Second)
+		}
+
+		listResult, err := kctrl.DoCommand(context.Background(), map[string]interface{}{
+			"command": "list_trials",
+			"limit":   float64(2),
+		})
+		if err != nil {
+			t.Fatalf("list_trials failed: %v", err)
+		}
+		trials, ok := listResult["trials"].([]interface{})
+		if !ok || len(trials) != 2 {
+			t.Fatalf("expected 2 trials with limit=2, got %v", listResult["trials"])
+		}
+
+		first := trials[0].(map[string]interface{})
+		second := trials[1].(map[string]interface{})
+		if first["trial_id"] != trialIDs[1] || second["trial_id"] != trialIDs[2] {
+			t.Errorf("expected the last 2 trials (%s, %s), got %v, %v", trialIDs[1], trialIDs[2], first["trial_id"], second["trial_id"])
+		}
+	})
+}
+
 func TestExecuteCycle(t *testing.T) {
 	t.Run("moves to pour_prep then back to resting", func(t *testing.T) {
 		logger := logging.NewTestLogger(t)
@@ -427,3 +610,612 @@ func TestExecuteCycle(t *testing.T) {
 		}
 	})
 }
+
+func TestMetrics(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+	deps, cfg := testDeps()
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger, WithMetricsRegistry(prometheus.NewRegistry()))
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+
+	if _, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "execute_cycle"}); err != nil {
+		t.Fatalf("execute_cycle failed: %v", err)
+	}
+
+	result, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "metrics"})
+	if err != nil {
+		t.Fatalf("metrics command failed: %v", err)
+	}
+
+	text, ok := result["metrics"].(string)
+	if !ok {
+		t.Fatalf("expected metrics response to contain a string, got %T", result["metrics"])
+	}
+	if !strings.Contains(text, "kettle_cycles_total") {
+		t.Errorf("expected metrics snapshot to contain kettle_cycles_total, got:\n%s", text)
+	}
+	if !strings.Contains(text, "kettle_cycle_duration_seconds") {
+		t.Errorf("expected metrics snapshot to contain kettle_cycle_duration_seconds, got:\n%s", text)
+	}
+	if !strings.Contains(text, "kettle_arm_wait_seconds") {
+		t.Errorf("expected metrics snapshot to contain kettle_arm_wait_seconds, got:\n%s", text)
+	}
+}
+
+func TestMetricsServer(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+	deps, cfg := testDeps()
+	cfg.MetricsAddr = "127.0.0.1:0"
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger, WithMetricsRegistry(prometheus.NewRegistry()))
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+	if kctrl.metricsServer == nil {
+		t.Fatal("expected metrics_addr to start a metrics server")
+	}
+
+	if err := kctrl.Close(context.Background()); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+}
+
+func TestTrialActiveGauge(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "trial-active-gauge-test")
+	deps, cfg := testDeps()
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger, WithMetricsRegistry(prometheus.NewRegistry()))
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+	trialActive := kctrl.metrics.kettleTrialActive
+
+	if got := testutil.ToFloat64(trialActive.WithLabelValues(name.Name)); got != 0 {
+		t.Errorf("expected kettle_trial_active=0 before start, got %v", got)
+	}
+
+	if _, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "start"}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if got := testutil.ToFloat64(trialActive.WithLabelValues(name.Name)); got != 1 {
+		t.Errorf("expected kettle_trial_active=1 while running, got %v", got)
+	}
+
+	if _, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "stop"}); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if got := testutil.ToFloat64(trialActive.WithLabelValues(name.Name)); got != 0 {
+		t.Errorf("expected kettle_trial_active=0 after stop, got %v", got)
+	}
+}
+
+func TestScheduledTrial(t *testing.T) {
+	t.Run("stops itself after reaching target cycle count", func(t *testing.T) {
+		logger := logging.NewTestLogger(t)
+		name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+		deps, cfg := testDeps()
+
+		ctrl, _ := NewController(context.Background(), deps, name, cfg, logger)
+		kctrl := ctrl.(*kettleCycleTestController)
+
+		_, err := kctrl.DoCommand(context.Background(), map[string]interface{}{
+			"command": "start",
+			"cycles":  float64(1),
+		})
+		if err != nil {
+			t.Fatalf("start failed: %v", err)
+		}
+
+		state := waitForTerminalState(t, kctrl, 5*time.Second)
+		if state["state"] != "completed" {
+			t.Errorf("expected state=completed, got %v", state["state"])
+		}
+		if state["completed_cycles"] != 1 {
+			t.Errorf("expected completed_cycles=1, got %v", state["completed_cycles"])
+		}
+		recentCycles, ok := state["recent_cycles"].([]interface{})
+		if !ok || len(recentCycles) != 1 {
+			t.Fatalf("expected 1 recent_cycles entry, got %v", state["recent_cycles"])
+		}
+		if rec, ok := recentCycles[0].(map[string]interface{}); !ok || rec["error"] != "" {
+			t.Errorf("expected recent_cycles[0].error to be empty, got %v", recentCycles[0])
+		}
+
+		// stop should read back the terminal state rather than erroring.
+		result, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "stop"})
+		if err != nil {
+			t.Fatalf("stop on an already-finished trial should not error: %v", err)
+		}
+		if result["state"] != "completed" {
+			t.Errorf("expected stop to report state=completed, got %v", result["state"])
+		}
+	})
+
+	t.Run("abort_on_error halts the run on the first failed cycle", func(t *testing.T) {
+		logger := logging.NewTestLogger(t)
+		name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+
+		pourPrepSwitch := inject.NewSwitch("pour-prep")
+		pourPrepSwitch.SetPositionFunc = func(ctx context.Context, position uint32, extra map[string]interface{}) error {
+			return errors.New("switch error")
+		}
+
+		deps := resource.Dependencies{
+			resource.NewName(arm.API, "test-arm"):           inject.NewArm("test-arm"),
+			resource.NewName(toggleswitch.API, "resting"):   inject.NewSwitch("resting"),
+			resource.NewName(toggleswitch.API, "pour-prep"): pourPrepSwitch,
+		}
+		cfg := &Config{
+			Arm:              "test-arm",
+			RestingPosition:  "resting",
+			PourPrepPosition: "pour-prep",
+		}
+
+		ctrl, _ := NewController(context.Background(), deps, name, cfg, logger)
+		kctrl := ctrl.(*kettleCycleTestController)
+
+		_, err := kctrl.DoCommand(context.Background(), map[string]interface{}{
+			"command":        "start",
+			"cycles":         float64(5),
+			"abort_on_error": true,
+		})
+		if err != nil {
+			t.Fatalf("start failed: %v", err)
+		}
+
+		state := waitForTerminalState(t, kctrl, 5*time.Second)
+		if state["state"] != "failed" {
+			t.Errorf("expected state=failed, got %v", state["state"])
+		}
+		if state["failed_cycles"] != 1 {
+			t.Errorf("expected failed_cycles=1, got %v", state["failed_cycles"])
+		}
+		if state["completed_cycles"] != 0 {
+			t.Errorf("expected completed_cycles=0, got %v", state["completed_cycles"])
+		}
+		recentCycles, ok := state["recent_cycles"].([]interface{})
+		if !ok || len(recentCycles) != 1 {
+			t.Fatalf("expected 1 recent_cycles entry, got %v", state["recent_cycles"])
+		}
+		if rec, ok := recentCycles[0].(map[string]interface{}); !ok || rec["error"] != "switch error" {
+			t.Errorf("expected recent_cycles[0].error=%q, got %v", "switch error", recentCycles[0])
+		}
+	})
+
+	t.Run("max_duration_ms stops an unbounded run", func(t *testing.T) {
+		logger := logging.NewTestLogger(t)
+		name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+		deps, cfg := testDeps()
+
+		ctrl, _ := NewController(context.Background(), deps, name, cfg, logger)
+		kctrl := ctrl.(*kettleCycleTestController)
+
+		_, err := kctrl.DoCommand(context.Background(), map[string]interface{}{
+			"command":         "start",
+			"max_duration_ms": float64(1),
+		})
+		if err != nil {
+			t.Fatalf("start failed: %v", err)
+		}
+
+		state := waitForTerminalState(t, kctrl, 5*time.Second)
+		if state["state"] != "aborted" {
+			t.Errorf("expected state=aborted, got %v", state["state"])
+		}
+
+		kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "stop"})
+	})
+}
+
+// fakeClock is a test double for clock that advances only when Advance is
+// called, letting tests deterministically drive cycleLoop's pacing and
+// backoff without real sleeps.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeClockWaiter
+}
+
+type fakeClockWaiter struct {
+	target time.Time
+	ch     chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	target := f.now.Add(d)
+	if !f.now.Before(target) {
+		ch <- f.now
+		return ch
+	}
+	f.waiters = append(f.waiters, fakeClockWaiter{target: target, ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d, firing any waiter whose target has
+// now been reached.
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+	remaining := f.waiters[:0]
+	for _, w := range f.waiters {
+		if !f.now.Before(w.target) {
+			w.ch <- f.now
+		} else {
+			remaining = append(remaining, w)
+		}
+	}
+	f.waiters = remaining
+}
+
+// fakeEventSink records every event it receives, in order, for assertions.
+type fakeEventSink struct {
+	mu     sync.Mutex
+	events []map[string]interface{}
+}
+
+func (f *fakeEventSink) HandleEvent(event map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+}
+
+func (f *fakeEventSink) recorded() []map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]map[string]interface{}, len(f.events))
+	copy(out, f.events)
+	return out
+}
+
+func TestEventBroadcaster(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+	deps, cfg := testDeps()
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+
+	sink := &fakeEventSink{}
+	kctrl.events.addSink(sink)
+
+	_, err = kctrl.DoCommand(context.Background(), map[string]interface{}{
+		"command": "start",
+		"cycles":  float64(1),
+	})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	waitForTerminalState(t, kctrl, 5*time.Second)
+	if _, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "stop"}); err != nil {
+		t.Fatalf("stop on an already-finished trial should not error: %v", err)
+	}
+
+	events := sink.recorded()
+	if len(events) == 0 {
+		t.Fatal("expected at least one event to be published")
+	}
+
+	var sawStart, sawCompleted bool
+	var lastSeq int64
+	for i, e := range events {
+		seq, ok := e["seq"].(int64)
+		if !ok || seq <= lastSeq {
+			t.Fatalf("event %d: seq not monotonically increasing: %v (previous %d)", i, e["seq"], lastSeq)
+		}
+		lastSeq = seq
+
+		switch e["type"] {
+		case "trial_started":
+			sawStart = true
+		case "cycle_completed":
+			sawCompleted = true
+		}
+	}
+	if !sawStart {
+		t.Error("expected a trial_started event")
+	}
+	if !sawCompleted {
+		t.Error("expected a cycle_completed event")
+	}
+}
+
+func TestEventsDoCommand(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+	deps, cfg := testDeps()
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+
+	_, err = kctrl.DoCommand(context.Background(), map[string]interface{}{
+		"command": "start",
+		"cycles":  float64(1),
+	})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	waitForTerminalState(t, kctrl, 5*time.Second)
+
+	result, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "events"})
+	if err != nil {
+		t.Fatalf("events command failed: %v", err)
+	}
+	events, ok := result["events"].([]map[string]interface{})
+	if !ok || len(events) == 0 {
+		t.Fatalf("expected at least one event, got %v", result["events"])
+	}
+
+	firstSeq, _ := events[0]["seq"].(int64)
+	result, err = kctrl.DoCommand(context.Background(), map[string]interface{}{
+		"command": "events",
+		"since":   float64(firstSeq),
+	})
+	if err != nil {
+		t.Fatalf("events command with since failed: %v", err)
+	}
+	sinceEvents, _ := result["events"].([]map[string]interface{})
+	for _, e := range sinceEvents {
+		if seq, _ := e["seq"].(int64); seq <= firstSeq {
+			t.Errorf("expected only events after seq %d, got seq %v", firstSeq, e["seq"])
+		}
+	}
+}
+
+func TestBackoffDuration(t *testing.T) {
+	base := 100 * time.Millisecond
+	max := 10 * time.Second
+
+	cases := []struct {
+		n        int
+		unjitted time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{10, max}, // 100ms*2^9 overflows max and clamps
+	}
+
+	for _, tc := range cases {
+		lower := time.Duration(float64(tc.unjitted) * 0.8)
+		upper := time.Duration(float64(tc.unjitted) * 1.2)
+		for i := 0; i < 50; i++ {
+			d := backoffDuration(base, max, tc.n)
+			if d < lower || d > upper {
+				t.Fatalf("n=%d: backoffDuration=%v, want in [%v, %v]", tc.n, d, lower, upper)
+			}
+		}
+	}
+}
+
+func TestDegradedAndResume(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+
+	pourPrepSwitch := inject.NewSwitch("pour-prep")
+	pourPrepSwitch.SetPositionFunc = func(ctx context.Context, position uint32, extra map[string]interface{}) error {
+		return errors.New("switch error")
+	}
+
+	deps := resource.Dependencies{
+		resource.NewName(arm.API, "test-arm"):           inject.NewArm("test-arm"),
+		resource.NewName(toggleswitch.API, "resting"):   inject.NewSwitch("resting"),
+		resource.NewName(toggleswitch.API, "pour-prep"): pourPrepSwitch,
+	}
+	cfg := &Config{
+		Arm:                     "test-arm",
+		RestingPosition:         "resting",
+		PourPrepPosition:        "pour-prep",
+		ConsecutiveFailureLimit: 2,
+		FailureBackoffBaseMs:    10,
+		FailureBackoffMaxMs:     50,
+	}
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+
+	fc := newFakeClock()
+	kctrl.clk = fc
+
+	stopPump := make(chan struct{})
+	var pumpWG sync.WaitGroup
+	pumpWG.Add(1)
+	go func() {
+		defer pumpWG.Done()
+		for {
+			select {
+			case <-stopPump:
+				return
+			default:
+				fc.Advance(time.Second)
+				time.Sleep(2 * time.Millisecond)
+			}
+		}
+	}()
+
+	if _, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "start"}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	state := waitForTerminalState(t, kctrl, 5*time.Second)
+	if state["state"] != "degraded" {
+		t.Fatalf("expected state=degraded after repeated failures, got %v", state["state"])
+	}
+	trialID := state["trial_id"]
+
+	if got := testutil.ToFloat64(kctrl.metrics.kettleTrialActive.WithLabelValues(name.Name)); got != 0 {
+		t.Errorf("expected kettle_trial_active=0 while degraded, got %v", got)
+	}
+
+	// Clear the failure before resuming so the loop can make progress.
+	pourPrepSwitch.SetPositionFunc = nil
+
+	result, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "resume"})
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if result["trial_id"] != trialID {
+		t.Errorf("expected resume to keep trial_id %v, got %v", trialID, result["trial_id"])
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		st := kctrl.GetState()
+		if st["state"] == "running" && st["completed_cycles"].(int) > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	close(stopPump)
+	pumpWG.Wait()
+	kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "stop"})
+
+	finalState := kctrl.GetState()
+	if c, _ := finalState["completed_cycles"].(int); c == 0 {
+		t.Errorf("expected at least one completed cycle after resume, got state %v", finalState)
+	}
+}
+
+// TestTrialStatePersistence simulates a crash: it starts an unbounded trial,
+// lets a couple of cycles complete, closes the controller without stopping
+// the trial, then builds a second controller against the same
+// trial_state_path and asserts it picks up cycling from the persisted count
+// instead of starting over.
+func TestTrialStatePersistence(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+	deps, cfg := testDeps()
+	cfg.TrialStatePath = filepath.Join(t.TempDir(), "trial-state.json")
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+
+	if _, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "start"}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if c, _ := kctrl.GetState()["completed_cycles"].(int); c >= 2 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	completedBeforeRestart, _ := kctrl.GetState()["completed_cycles"].(int)
+	if completedBeforeRestart < 2 {
+		t.Fatalf("expected at least 2 completed cycles before simulating a crash, got %d", completedBeforeRestart)
+	}
+
+	// Close without stopping the trial, simulating a crash rather than an
+	// operator-initiated stop.
+	if err := kctrl.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	ctrl2, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("second NewController failed: %v", err)
+	}
+	kctrl2 := ctrl2.(*kettleCycleTestController)
+	defer kctrl2.Close(context.Background())
+
+	state := kctrl2.GetState()
+	if state["state"] != "running" {
+		t.Fatalf("expected rehydrated trial to resume running, got %v", state["state"])
+	}
+	if c, _ := state["completed_cycles"].(int); c < completedBeforeRestart {
+		t.Fatalf("expected rehydrated completed_cycles >= %d, got %v", completedBeforeRestart, state["completed_cycles"])
+	}
+
+	deadline = time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if c, _ := kctrl2.GetState()["completed_cycles"].(int); c > completedBeforeRestart {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	final, _ := kctrl2.GetState()["completed_cycles"].(int)
+	if final <= completedBeforeRestart {
+		t.Fatalf("expected completed_cycles to keep growing after restart, stuck at %d", final)
+	}
+
+	kctrl2.DoCommand(context.Background(), map[string]interface{}{"command": "stop"})
+}
+
+// TestAbandonTrial checks that abandon_trial wipes both the in-memory and
+// persisted trial state, so a subsequent restart finds nothing to rehydrate.
+func TestAbandonTrial(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+	deps, cfg := testDeps()
+	cfg.TrialStatePath = filepath.Join(t.TempDir(), "trial-state.json")
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+	defer kctrl.Close(context.Background())
+
+	if _, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "start"}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if c, _ := kctrl.GetState()["completed_cycles"].(int); c >= 1 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if _, err := kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "abandon_trial"}); err != nil {
+		t.Fatalf("abandon_trial failed: %v", err)
+	}
+	if state := kctrl.GetState(); state["state"] != "idle" {
+		t.Errorf("expected state=idle after abandon_trial, got %v", state["state"])
+	}
+
+	if _, err := kctrl.trialStateStore.Load(); err != nil {
+		t.Fatalf("Load after abandon_trial failed: %v", err)
+	} else if p, _ := kctrl.trialStateStore.Load(); p != nil {
+		t.Errorf("expected no persisted trial after abandon_trial, got %+v", p)
+	}
+}
@@ -0,0 +1,198 @@
+// Package program parses and validates the scripted cycle programs that
+// replace the controller's hardcoded pour_prep->resting sequence. A Program
+// is an ordered list of Steps; the controller compiles one once in
+// NewController and walks it on every execute_cycle.
+package program
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StepType identifies one of the step kinds a Program may contain.
+type StepType string
+
+const (
+	StepMoveToSwitch      StepType = "move_to_switch"
+	StepSleep             StepType = "sleep"
+	StepWaitArmStopped    StepType = "wait_arm_stopped"
+	StepStartForceCapture StepType = "start_force_capture"
+	StepEndForceCapture   StepType = "end_force_capture"
+	StepAssertForce       StepType = "assert_force"
+	StepCheckpoint        StepType = "checkpoint"
+)
+
+// AssertOp is the comparison a assert_force step applies to a bound force
+// reading.
+type AssertOp string
+
+const (
+	AssertLess    AssertOp = "<"
+	AssertGreater AssertOp = ">"
+	AssertBetween AssertOp = "between"
+)
+
+// Step is one instruction in a Program. Only the fields relevant to Type are
+// populated; the rest are left zero. Field names are shared across step
+// types where their meaning doesn't conflict, to keep the on-disk program
+// format compact.
+type Step struct {
+	Type StepType `json:"type" yaml:"type"`
+
+	// move_to_switch
+	Switch   string `json:"switch,omitempty" yaml:"switch,omitempty"`
+	Position uint32 `json:"position,omitempty" yaml:"position,omitempty"`
+
+	// sleep
+	DurationMs int64 `json:"duration_ms,omitempty" yaml:"duration_ms,omitempty"`
+
+	// wait_arm_stopped
+	TimeoutMs int64 `json:"timeout_ms,omitempty" yaml:"timeout_ms,omitempty"`
+
+	// end_force_capture: Var names the variable the capture result binds to,
+	// for later assert_force steps to read.
+	Var string `json:"var,omitempty" yaml:"var,omitempty"`
+
+	// assert_force
+	Of        string   `json:"of,omitempty" yaml:"of,omitempty"`       // variable bound by a prior end_force_capture
+	Field     string   `json:"field,omitempty" yaml:"field,omitempty"` // e.g. "max_force", "mean_force"
+	Op        AssertOp `json:"op,omitempty" yaml:"op,omitempty"`
+	Threshold float64  `json:"threshold,omitempty" yaml:"threshold,omitempty"` // used by "<" and ">"
+	Min       float64  `json:"min,omitempty" yaml:"min,omitempty"`             // used by "between"
+	Max       float64  `json:"max,omitempty" yaml:"max,omitempty"`             // used by "between"
+	FailFast  *bool    `json:"fail_fast,omitempty" yaml:"fail_fast,omitempty"` // default true: stop the program on failure
+
+	// checkpoint
+	Label string `json:"label,omitempty" yaml:"label,omitempty"`
+}
+
+// FailsFast reports whether a failed assert_force step should stop the
+// remaining steps. Unset (nil) defaults to true.
+func (s Step) FailsFast() bool {
+	return s.FailFast == nil || *s.FailFast
+}
+
+// Program is an ordered, validated list of Steps compiled from a
+// Config.Program or Config.ProgramPath source.
+type Program struct {
+	Steps []Step `json:"steps" yaml:"steps"`
+}
+
+// Parse decodes data as a cycle program. The format is detected by content:
+// data starting with '{' or '[' is parsed as JSON, everything else as YAML
+// (which is a superset of JSON, so this only matters for error messages).
+func Parse(data []byte) (*Program, error) {
+	trimmed := bytes.TrimSpace(data)
+	var p Program
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("parsing program as JSON: %w", err)
+		}
+	} else {
+		if err := yaml.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("parsing program as YAML: %w", err)
+		}
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate checks that every step is structurally well-formed: a known
+// type with its required fields set. It does not know about the
+// controller's switches or force sensor -- that's ValidateSwitches and the
+// controller's own nil-force-sensor check.
+func (p *Program) Validate() error {
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("program: at least one step is required")
+	}
+	for i, s := range p.Steps {
+		if err := s.validate(); err != nil {
+			return fmt.Errorf("program: step %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s Step) validate() error {
+	switch s.Type {
+	case StepMoveToSwitch:
+		if s.Switch == "" {
+			return fmt.Errorf("move_to_switch: switch is required")
+		}
+	case StepSleep:
+		if s.DurationMs <= 0 {
+			return fmt.Errorf("sleep: duration_ms must be > 0")
+		}
+	case StepWaitArmStopped:
+		// TimeoutMs <= 0 is allowed: the controller falls back to its own default.
+	case StepStartForceCapture:
+	case StepEndForceCapture:
+		if s.Var == "" {
+			return fmt.Errorf("end_force_capture: var is required")
+		}
+	case StepAssertForce:
+		if s.Of == "" {
+			return fmt.Errorf("assert_force: of is required")
+		}
+		if s.Field == "" {
+			return fmt.Errorf("assert_force: field is required")
+		}
+		switch s.Op {
+		case AssertLess, AssertGreater:
+		case AssertBetween:
+			if s.Min >= s.Max {
+				return fmt.Errorf("assert_force: between requires min < max")
+			}
+		case "":
+			return fmt.Errorf("assert_force: op is required")
+		default:
+			return fmt.Errorf("assert_force: unknown op %q", s.Op)
+		}
+	case StepCheckpoint:
+		if s.Label == "" {
+			return fmt.Errorf("checkpoint: label is required")
+		}
+	case "":
+		return fmt.Errorf("missing type")
+	default:
+		return fmt.Errorf("unknown step type %q", s.Type)
+	}
+	return nil
+}
+
+// ValidateSwitches checks that every move_to_switch step names a switch
+// present in available, which the controller builds from its declared
+// dependencies. It returns the first unresolvable name it finds.
+func (p *Program) ValidateSwitches(available map[string]bool) error {
+	for i, s := range p.Steps {
+		if s.Type != StepMoveToSwitch {
+			continue
+		}
+		if !available[s.Switch] {
+			return fmt.Errorf("program: step %d: move_to_switch references undeclared switch %q", i, s.Switch)
+		}
+	}
+	return nil
+}
+
+// Default synthesizes the controller's historical hardcoded sequence --
+// move to pour_prep, capture force while returning to resting, then settle
+// -- for when no Config.Program or Config.ProgramPath is configured.
+func Default() *Program {
+	return &Program{
+		Steps: []Step{
+			{Type: StepMoveToSwitch, Switch: "pour_prep", Position: 2},
+			{Type: StepSleep, DurationMs: 1000},
+			{Type: StepStartForceCapture},
+			{Type: StepMoveToSwitch, Switch: "resting", Position: 2},
+			{Type: StepWaitArmStopped},
+			{Type: StepEndForceCapture, Var: "capture"},
+			{Type: StepSleep, DurationMs: 1000},
+		},
+	}
+}
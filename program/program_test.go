@@ -0,0 +1,119 @@
+package program
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseJSON(t *testing.T) {
+	p, err := Parse([]byte(`{"steps":[{"type":"move_to_switch","switch":"pour_prep","position":2},{"type":"sleep","duration_ms":500}]}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(p.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(p.Steps))
+	}
+	if p.Steps[0].Type != StepMoveToSwitch || p.Steps[0].Switch != "pour_prep" || p.Steps[0].Position != 2 {
+		t.Errorf("unexpected step 0: %+v", p.Steps[0])
+	}
+}
+
+func TestParseYAML(t *testing.T) {
+	p, err := Parse([]byte(`
+steps:
+  - type: move_to_switch
+    switch: resting
+    position: 1
+  - type: wait_arm_stopped
+    timeout_ms: 5000
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(p.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(p.Steps))
+	}
+	if p.Steps[1].Type != StepWaitArmStopped || p.Steps[1].TimeoutMs != 5000 {
+		t.Errorf("unexpected step 1: %+v", p.Steps[1])
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"malformed json", `{"steps":[{]}`, "parsing program"},
+		{"malformed yaml", "steps:\n  - type: sleep\n  bad indent:\n", "parsing program"},
+		{"no steps", `{"steps":[]}`, "at least one step"},
+		{"missing type", `{"steps":[{}]}`, "missing type"},
+		{"unknown type", `{"steps":[{"type":"fly_away"}]}`, "unknown step type"},
+		{"move_to_switch missing switch", `{"steps":[{"type":"move_to_switch"}]}`, "switch is required"},
+		{"sleep missing duration", `{"steps":[{"type":"sleep"}]}`, "duration_ms must be"},
+		{"end_force_capture missing var", `{"steps":[{"type":"end_force_capture"}]}`, "var is required"},
+		{"assert_force missing of", `{"steps":[{"type":"assert_force","field":"max_force","op":"<","threshold":10}]}`, "of is required"},
+		{"assert_force missing field", `{"steps":[{"type":"assert_force","of":"capture","op":"<","threshold":10}]}`, "field is required"},
+		{"assert_force missing op", `{"steps":[{"type":"assert_force","of":"capture","field":"max_force"}]}`, "op is required"},
+		{"assert_force bad op", `{"steps":[{"type":"assert_force","of":"capture","field":"max_force","op":"~="}]}`, "unknown op"},
+		{"assert_force bad between", `{"steps":[{"type":"assert_force","of":"capture","field":"max_force","op":"between","min":10,"max":5}]}`, "min < max"},
+		{"checkpoint missing label", `{"steps":[{"type":"checkpoint"}]}`, "label is required"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.data))
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.want)
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got %q", tc.want, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidateSwitches(t *testing.T) {
+	p, err := Parse([]byte(`{"steps":[{"type":"move_to_switch","switch":"pour_prep","position":2},{"type":"move_to_switch","switch":"side_vent","position":1}]}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if err := p.ValidateSwitches(map[string]bool{"pour_prep": true, "resting": true}); err == nil {
+		t.Fatal("expected error for undeclared switch side_vent")
+	} else if !strings.Contains(err.Error(), "side_vent") {
+		t.Errorf("expected error naming side_vent, got %q", err.Error())
+	}
+
+	if err := p.ValidateSwitches(map[string]bool{"pour_prep": true, "side_vent": true}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestFailsFast(t *testing.T) {
+	no := false
+	cases := []struct {
+		name string
+		step Step
+		want bool
+	}{
+		{"unset defaults true", Step{Type: StepAssertForce}, true},
+		{"explicit false", Step{Type: StepAssertForce, FailFast: &no}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.step.FailsFast(); got != tc.want {
+				t.Errorf("FailsFast() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	p := Default()
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Default() program failed Validate: %v", err)
+	}
+	if err := p.ValidateSwitches(map[string]bool{"pour_prep": true, "resting": true}); err != nil {
+		t.Fatalf("Default() program failed ValidateSwitches: %v", err)
+	}
+}
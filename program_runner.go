@@ -0,0 +1,211 @@
+package kettlecycletest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"kettlecycletest/program"
+)
+
+// defaultWaitArmStoppedTimeout is waitForArmStopped's historical timeout; a
+// wait_arm_stopped step without timeout_ms falls back to it.
+const defaultWaitArmStoppedTimeout = 10 * time.Second
+
+// runProgram executes s.program's steps in order for one cycle of trialID,
+// returning every end_force_capture result keyed by its step's Var. An
+// assert_force failure is always reported as an error once the program
+// finishes; if the failing step is fail-fast (the default), runProgram
+// returns immediately instead of running the remaining steps.
+func (s *kettleCycleTestController) runProgram(ctx context.Context, trialID string) (map[string]map[string]interface{}, error) {
+	captures := make(map[string]map[string]interface{})
+	var failures []string
+
+	// openCaptureStep tracks the step index of an outstanding
+	// start_force_capture, or -1 if none is open. If a later step fails and
+	// returns before the matching end_force_capture runs, the deferred
+	// cleanup below ends it anyway -- otherwise the force sensor is stuck
+	// non-idle and every subsequent start_force_capture silently fails until
+	// captureTimeout eventually fires.
+	openCaptureStep := -1
+	defer func() {
+		if openCaptureStep >= 0 {
+			s.endForceCaptureStep(ctx, openCaptureStep, trialID)
+		}
+	}()
+
+	for i, step := range s.program.Steps {
+		select {
+		case <-ctx.Done():
+			return captures, ctx.Err()
+		default:
+		}
+
+		switch step.Type {
+		case program.StepMoveToSwitch:
+			sw, ok := s.switches[step.Switch]
+			if !ok {
+				// Unreachable in practice: NewController rejects a program
+				// referencing an undeclared switch before it ever runs.
+				return captures, fmt.Errorf("step %d: unknown switch %q", i, step.Switch)
+			}
+			if err := sw.SetPosition(ctx, step.Position, nil); err != nil {
+				return captures, fmt.Errorf("step %d: move_to_switch %s: %w", i, step.Switch, err)
+			}
+
+		case program.StepSleep:
+			select {
+			case <-ctx.Done():
+				return captures, ctx.Err()
+			case <-time.After(time.Duration(step.DurationMs) * time.Millisecond):
+			}
+
+		case program.StepWaitArmStopped:
+			timeout := defaultWaitArmStoppedTimeout
+			if step.TimeoutMs > 0 {
+				timeout = time.Duration(step.TimeoutMs) * time.Millisecond
+			}
+			if err := s.waitForArmStopped(ctx, timeout); err != nil {
+				s.logger.Warnf("step %d: error waiting for arm to stop: %v", i, err)
+			}
+
+		case program.StepStartForceCapture:
+			s.startForceCaptureStep(ctx, i)
+			openCaptureStep = i
+
+		case program.StepEndForceCapture:
+			if capture, ok := s.endForceCaptureStep(ctx, i, trialID); ok {
+				captures[step.Var] = capture
+			}
+			openCaptureStep = -1
+
+		case program.StepAssertForce:
+			ok, value, err := evaluateAssertForce(step, captures)
+			if err != nil {
+				return captures, fmt.Errorf("step %d: %w", i, err)
+			}
+			if !ok {
+				msg := assertFailureMessage(step, value)
+				failures = append(failures, msg)
+				s.events.Publish("assert_force_failed", map[string]interface{}{
+					"controller": s.name.Name,
+					"trial_id":   trialID,
+					"step":       i,
+					"field":      step.Field,
+					"op":         string(step.Op),
+					"threshold":  step.Threshold,
+					"min":        step.Min,
+					"max":        step.Max,
+					"value":      value,
+				})
+				if step.FailsFast() {
+					return captures, fmt.Errorf("step %d: assert_force: %s", i, msg)
+				}
+			}
+
+		case program.StepCheckpoint:
+			s.events.Publish("checkpoint", map[string]interface{}{
+				"controller": s.name.Name,
+				"trial_id":   trialID,
+				"label":      step.Label,
+			})
+		}
+	}
+
+	if len(failures) > 0 {
+		return captures, fmt.Errorf("assert_force failed: %s", strings.Join(failures, "; "))
+	}
+	return captures, nil
+}
+
+// startForceCaptureStep is a no-op when no force sensor is configured,
+// matching the rest of the controller's optional-sensor handling.
+func (s *kettleCycleTestController) startForceCaptureStep(ctx context.Context, step int) {
+	if s.forceSensor == nil {
+		return
+	}
+
+	s.mu.Lock()
+	cmd := map[string]interface{}{"command": "start_capture"}
+	if s.activeTrial != nil {
+		cmd["trial_id"] = s.activeTrial.trialID
+		cmd["cycle_count"] = s.activeTrial.cycleCount
+	}
+	s.mu.Unlock()
+
+	if _, err := s.forceSensor.DoCommand(ctx, cmd); err != nil {
+		s.logger.Warnf("step %d: failed to start force capture: %v", step, err)
+	}
+}
+
+// endForceCaptureStep ends the active capture and, on success, publishes a
+// force_capture_summary event and updates the peak/mean gauges. ok is false
+// if there's no force sensor configured or the end_capture call failed.
+func (s *kettleCycleTestController) endForceCaptureStep(ctx context.Context, step int, trialID string) (map[string]interface{}, bool) {
+	if s.forceSensor == nil {
+		return nil, false
+	}
+
+	result, err := s.forceSensor.DoCommand(ctx, map[string]interface{}{"command": "end_capture"})
+	if err != nil {
+		s.logger.Warnf("step %d: failed to end force capture: %v", step, err)
+		return nil, false
+	}
+	s.logger.Infof("force capture: %v", result)
+
+	if peak, ok := result["max_force"].(float64); ok {
+		s.metrics.kettleForcePeakNewtons.WithLabelValues(s.name.Name).Set(peak)
+	}
+	if mean, ok := result["mean_force"].(float64); ok {
+		s.metrics.kettleForceMeanNewtons.WithLabelValues(s.name.Name).Set(mean)
+	}
+	if durationMs, ok := result["capture_duration_ms"].(float64); ok {
+		s.metrics.kettleForceCaptureDuration.WithLabelValues(s.name.Name).Observe(durationMs / 1000)
+	}
+	s.events.Publish("force_capture_summary", map[string]interface{}{
+		"controller":    s.name.Name,
+		"trial_id":      trialID,
+		"force_capture": result,
+	})
+	return result, true
+}
+
+// evaluateAssertForce reads step.Field out of the capture bound to step.Of
+// and checks it against step.Op. An error means the program is
+// misconfigured (the variable was never bound, or doesn't carry that
+// field) rather than a failed assertion.
+func evaluateAssertForce(step program.Step, captures map[string]map[string]interface{}) (ok bool, value float64, err error) {
+	capture, bound := captures[step.Of]
+	if !bound {
+		return false, 0, fmt.Errorf("assert_force: no capture bound to variable %q", step.Of)
+	}
+	value, ok = capture[step.Field].(float64)
+	if !ok {
+		return false, 0, fmt.Errorf("assert_force: field %q not present in capture %q", step.Field, step.Of)
+	}
+
+	switch step.Op {
+	case program.AssertLess:
+		return value < step.Threshold, value, nil
+	case program.AssertGreater:
+		return value > step.Threshold, value, nil
+	case program.AssertBetween:
+		return value >= step.Min && value <= step.Max, value, nil
+	default:
+		return false, value, fmt.Errorf("assert_force: unknown op %q", step.Op)
+	}
+}
+
+func assertFailureMessage(step program.Step, value float64) string {
+	switch step.Op {
+	case program.AssertLess:
+		return fmt.Sprintf("%s=%.2f, want < %.2f", step.Field, value, step.Threshold)
+	case program.AssertGreater:
+		return fmt.Sprintf("%s=%.2f, want > %.2f", step.Field, value, step.Threshold)
+	case program.AssertBetween:
+		return fmt.Sprintf("%s=%.2f, want between %.2f and %.2f", step.Field, value, step.Min, step.Max)
+	default:
+		return fmt.Sprintf("%s=%.2f", step.Field, value)
+	}
+}
@@ -0,0 +1,277 @@
+package kettlecycletest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.viam.com/rdk/components/arm"
+	toggleswitch "go.viam.com/rdk/components/switch"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// stubForceSensor is a minimal sensor.Sensor whose DoCommand is swappable
+// per test, for driving the controller's force-capture steps without a real
+// forceSensor state machine.
+type stubForceSensor struct {
+	resource.AlwaysRebuild
+
+	name      resource.Name
+	doCommand func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
+}
+
+func (s *stubForceSensor) Name() resource.Name { return s.name }
+
+func (s *stubForceSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func (s *stubForceSensor) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return s.doCommand(ctx, cmd)
+}
+
+func (s *stubForceSensor) Close(context.Context) error { return nil }
+
+func TestRunProgram_AssertForceShortCircuits(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+
+	var capturesSeen, checkpointsSeen int
+
+	forceSensorName := resource.NewName(resource.APINamespaceRDK.WithComponentType("sensor"), "force")
+	fs := &stubForceSensor{
+		name: forceSensorName,
+		doCommand: func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			switch cmd["command"] {
+			case "start_capture":
+				return map[string]interface{}{}, nil
+			case "end_capture":
+				capturesSeen++
+				return map[string]interface{}{"max_force": 10.0, "mean_force": 5.0}, nil
+			default:
+				return nil, errors.New("unsupported command")
+			}
+		},
+	}
+
+	deps := resource.Dependencies{
+		resource.NewName(arm.API, "test-arm"):           inject.NewArm("test-arm"),
+		resource.NewName(toggleswitch.API, "resting"):   inject.NewSwitch("resting"),
+		resource.NewName(toggleswitch.API, "pour-prep"): inject.NewSwitch("pour-prep"),
+		forceSensorName: fs,
+	}
+
+	cfg := &Config{
+		Arm:              "test-arm",
+		RestingPosition:  "resting",
+		PourPrepPosition: "pour-prep",
+		ForceSensor:      "force",
+		Program: `{"steps": [
+			{"type": "move_to_switch", "switch": "pour_prep", "position": 2},
+			{"type": "start_force_capture"},
+			{"type": "move_to_switch", "switch": "resting", "position": 2},
+			{"type": "end_force_capture", "var": "capture"},
+			{"type": "assert_force", "of": "capture", "field": "max_force", "op": "<", "threshold": 5},
+			{"type": "checkpoint", "label": "after_assert"}
+		]}`,
+	}
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+
+	_, err = kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "execute_cycle"})
+	if err == nil {
+		t.Fatal("expected execute_cycle to fail on assert_force")
+	}
+	if !strings.Contains(err.Error(), "assert_force") || !strings.Contains(err.Error(), "max_force=10.00") {
+		t.Errorf("expected error to name the failed assertion, got %q", err.Error())
+	}
+
+	if capturesSeen != 1 {
+		t.Errorf("expected end_force_capture to run once, ran %d times", capturesSeen)
+	}
+
+	events := kctrl.eventRing.Since(0, 0)
+	for _, e := range events {
+		if e["type"] == "checkpoint" {
+			checkpointsSeen++
+		}
+	}
+	if checkpointsSeen != 0 {
+		t.Errorf("expected checkpoint step after a fail-fast assert_force to be skipped, saw %d checkpoint events", checkpointsSeen)
+	}
+
+	sawAssertFailed := false
+	for _, e := range events {
+		if e["type"] == "assert_force_failed" {
+			sawAssertFailed = true
+		}
+	}
+	if !sawAssertFailed {
+		t.Error("expected an assert_force_failed event")
+	}
+}
+
+func TestRunProgram_AssertForceNotFailFastContinues(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+
+	forceSensorName := resource.NewName(resource.APINamespaceRDK.WithComponentType("sensor"), "force")
+	fs := &stubForceSensor{
+		name: forceSensorName,
+		doCommand: func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			switch cmd["command"] {
+			case "start_capture":
+				return map[string]interface{}{}, nil
+			case "end_capture":
+				return map[string]interface{}{"max_force": 10.0, "mean_force": 5.0}, nil
+			default:
+				return nil, errors.New("unsupported command")
+			}
+		},
+	}
+
+	deps := resource.Dependencies{
+		resource.NewName(arm.API, "test-arm"):           inject.NewArm("test-arm"),
+		resource.NewName(toggleswitch.API, "resting"):   inject.NewSwitch("resting"),
+		resource.NewName(toggleswitch.API, "pour-prep"): inject.NewSwitch("pour-prep"),
+		forceSensorName: fs,
+	}
+
+	cfg := &Config{
+		Arm:              "test-arm",
+		RestingPosition:  "resting",
+		PourPrepPosition: "pour-prep",
+		ForceSensor:      "force",
+		Program: `{"steps": [
+			{"type": "start_force_capture"},
+			{"type": "end_force_capture", "var": "capture"},
+			{"type": "assert_force", "of": "capture", "field": "max_force", "op": "<", "threshold": 5, "fail_fast": false},
+			{"type": "checkpoint", "label": "after_assert"}
+		]}`,
+	}
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+
+	_, err = kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "execute_cycle"})
+	if err == nil {
+		t.Fatal("expected execute_cycle to still report the cycle failed")
+	}
+
+	checkpointsSeen := 0
+	for _, e := range kctrl.eventRing.Since(0, 0) {
+		if e["type"] == "checkpoint" {
+			checkpointsSeen++
+		}
+	}
+	if checkpointsSeen != 1 {
+		t.Errorf("expected the checkpoint after a non-fail-fast assert_force to still run, saw %d checkpoint events", checkpointsSeen)
+	}
+}
+
+func TestRunProgram_EndsForceCaptureWhenALaterStepFails(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+
+	var captureEnded bool
+	forceSensorName := resource.NewName(resource.APINamespaceRDK.WithComponentType("sensor"), "force")
+	fs := &stubForceSensor{
+		name: forceSensorName,
+		doCommand: func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			switch cmd["command"] {
+			case "start_capture":
+				return map[string]interface{}{}, nil
+			case "end_capture":
+				captureEnded = true
+				return map[string]interface{}{"max_force": 10.0, "mean_force": 5.0}, nil
+			default:
+				return nil, errors.New("unsupported command")
+			}
+		},
+	}
+
+	faultingSwitch := inject.NewSwitch("resting")
+	faultingSwitch.SetPositionFunc = func(ctx context.Context, position uint32, extra map[string]interface{}) error {
+		return errors.New("arm faulted mid-motion")
+	}
+
+	deps := resource.Dependencies{
+		resource.NewName(arm.API, "test-arm"):           inject.NewArm("test-arm"),
+		resource.NewName(toggleswitch.API, "resting"):   faultingSwitch,
+		resource.NewName(toggleswitch.API, "pour-prep"): inject.NewSwitch("pour-prep"),
+		forceSensorName: fs,
+	}
+
+	cfg := &Config{
+		Arm:              "test-arm",
+		RestingPosition:  "resting",
+		PourPrepPosition: "pour-prep",
+		ForceSensor:      "force",
+		Program: `{"steps": [
+			{"type": "start_force_capture"},
+			{"type": "move_to_switch", "switch": "resting", "position": 2},
+			{"type": "end_force_capture", "var": "capture"}
+		]}`,
+	}
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+	kctrl := ctrl.(*kettleCycleTestController)
+
+	_, err = kctrl.DoCommand(context.Background(), map[string]interface{}{"command": "execute_cycle"})
+	if err == nil {
+		t.Fatal("expected execute_cycle to fail on the faulting move_to_switch step")
+	}
+	if !strings.Contains(err.Error(), "move_to_switch") {
+		t.Errorf("expected error to name the failed step, got %q", err.Error())
+	}
+	if !captureEnded {
+		t.Error("expected the outstanding force capture to be ended even though the program returned early")
+	}
+}
+
+func TestNewController_RejectsProgramWithUndeclaredSwitch(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+	deps, cfg := testDeps()
+	cfg.Program = `{"steps": [{"type": "move_to_switch", "switch": "side_vent", "position": 1}]}`
+
+	_, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err == nil {
+		t.Fatal("expected NewController to reject a program referencing an undeclared switch")
+	}
+	if !strings.Contains(err.Error(), "side_vent") {
+		t.Errorf("expected error to name side_vent, got %q", err.Error())
+	}
+}
+
+func TestNewController_ExtraSwitchFromConfig(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	name := resource.NewName(resource.APINamespaceRDK.WithServiceType("generic"), "test")
+	deps, cfg := testDeps()
+	cfg.Switches = map[string]string{"side_vent": "side-vent-switch"}
+	cfg.Program = `{"steps": [{"type": "move_to_switch", "switch": "side_vent", "position": 1}]}`
+	deps[resource.NewName(toggleswitch.API, "side-vent-switch")] = inject.NewSwitch("side-vent-switch")
+
+	ctrl, err := NewController(context.Background(), deps, name, cfg, logger)
+	if err != nil {
+		t.Fatalf("NewController failed: %v", err)
+	}
+
+	_, err = ctrl.(*kettleCycleTestController).DoCommand(context.Background(), map[string]interface{}{"command": "execute_cycle"})
+	if err != nil {
+		t.Fatalf("execute_cycle failed: %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package kettlecycletest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	defaultTrialLogMaxBytes = 10 * 1024 * 1024 // 10MB
+	defaultTrialLogMaxFiles = 5
+
+	// trialLogQueueCap bounds how many trial records can be buffered while a
+	// slow disk is still catching up. WriteTrial drops (and logs) rather than
+	// block once it's full, so a stuck disk can never stall samplingLoop or
+	// the capture command handlers that call it while holding fs.mu.
+	trialLogQueueCap = 256
+)
+
+// trialLogger appends one JSON object per trial to a size-rotated log file,
+// similar to lumberjack: once the active file exceeds maxBytes, it is renamed
+// to "<path>.1" (shifting older numbered files up) and a fresh file opened.
+//
+// Writes (including the fsync on each one) happen on a dedicated background
+// goroutine fed by a buffered queue, so WriteTrial -- called from
+// forceSensor.finalizeLocked while fs.mu is held -- never blocks on file I/O.
+type trialLogger struct {
+	logger   logging.Logger
+	path     string
+	maxBytes int64
+	maxFiles int
+
+	queue chan map[string]interface{}
+	done  chan struct{}
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newTrialLogger(path string, maxBytes int64, maxFiles int, logger logging.Logger) (*trialLogger, error) {
+	if maxBytes <= 0 {
+		maxBytes = defaultTrialLogMaxBytes
+	}
+	if maxFiles <= 0 {
+		maxFiles = defaultTrialLogMaxFiles
+	}
+
+	tl := &trialLogger{
+		logger:   logger,
+		path:     path,
+		maxBytes: maxBytes,
+		maxFiles: maxFiles,
+		queue:    make(chan map[string]interface{}, trialLogQueueCap),
+		done:     make(chan struct{}),
+	}
+	if err := tl.openLocked(); err != nil {
+		return nil, fmt.Errorf("opening trial log %q: %w", path, err)
+	}
+	go tl.run()
+	return tl, nil
+}
+
+func (tl *trialLogger) openLocked() error {
+	f, err := os.OpenFile(tl.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	tl.file = f
+	tl.size = info.Size()
+	return nil
+}
+
+// run drains queue and writes each record to disk, one at a time, until
+// queue is closed by Close.
+func (tl *trialLogger) run() {
+	defer close(tl.done)
+	for record := range tl.queue {
+		tl.writeRecord(record)
+	}
+}
+
+// WriteTrial hands record off to the background writer goroutine to be
+// appended as a single JSON line. If the queue is full -- a sustained slow
+// disk -- the record is dropped and logged rather than blocking the caller.
+func (tl *trialLogger) WriteTrial(record map[string]interface{}) {
+	select {
+	case tl.queue <- record:
+	default:
+		tl.logger.Warnf("trial log: write queue full, dropping record")
+	}
+}
+
+// writeRecord marshals and appends record, rotating the file first if it
+// would grow past maxBytes, then fsyncs. Failures are logged but never
+// returned -- a logging problem must never fail a trial. Only called from
+// run, so it owns tl.file/tl.size without contending with Close's use of
+// tl.mu for anything but the final handoff.
+func (tl *trialLogger) writeRecord(record map[string]interface{}) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		tl.logger.Warnf("trial log: failed to marshal record: %v", err)
+		return
+	}
+	data = append(data, '\n')
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if tl.file != nil && tl.size+int64(len(data)) > tl.maxBytes {
+		if err := tl.rotateLocked(); err != nil {
+			tl.logger.Warnf("trial log: rotation failed: %v", err)
+		}
+	}
+	if tl.file == nil {
+		tl.logger.Warnf("trial log: no open file, dropping record")
+		return
+	}
+
+	n, err := tl.file.Write(data)
+	if err != nil {
+		tl.logger.Warnf("trial log: write failed: %v", err)
+		return
+	}
+	tl.size += int64(n)
+
+	if err := tl.file.Sync(); err != nil {
+		tl.logger.Warnf("trial log: flush failed: %v", err)
+	}
+}
+
+// rotateLocked shifts "<path>.N" to "<path>.N+1" for N down to 1, dropping
+// anything past maxFiles, then moves the active file to "<path>.1" and opens
+// a fresh one in its place. Callers must hold tl.mu.
+func (tl *trialLogger) rotateLocked() error {
+	if tl.file != nil {
+		tl.file.Close()
+		tl.file = nil
+	}
+
+	oldest := fmt.Sprintf("%s.%d", tl.path, tl.maxFiles)
+	os.Remove(oldest)
+
+	for i := tl.maxFiles - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", tl.path, i)
+		dst := fmt.Sprintf("%s.%d", tl.path, i+1)
+		if _, err := os.Stat(src); err == nil {
+			os.Rename(src, dst)
+		}
+	}
+	if _, err := os.Stat(tl.path); err == nil {
+		os.Rename(tl.path, tl.path+".1")
+	}
+
+	return tl.openLocked()
+}
+
+// Close drains any queued records, stops the writer goroutine, and closes
+// the active file.
+func (tl *trialLogger) Close() error {
+	close(tl.queue)
+	<-tl.done
+
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	if tl.file == nil {
+		return nil
+	}
+	err := tl.file.Close()
+	tl.file = nil
+	return err
+}
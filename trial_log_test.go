@@ -0,0 +1,118 @@
+package kettlecycletest
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestTrialLogger(t *testing.T) {
+	t.Run("appends one JSON line per trial", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "trials.jsonl")
+		tl, err := newTrialLogger(path, 0, 0, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatalf("newTrialLogger failed: %v", err)
+		}
+
+		tl.WriteTrial(map[string]interface{}{"trial_id": "trial-1"})
+		tl.WriteTrial(map[string]interface{}{"trial_id": "trial-2"})
+		tl.Close() // drains the async write queue before we read the file back
+
+		lines := readLines(t, path)
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines, got %d", len(lines))
+		}
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+			t.Fatalf("failed to unmarshal record: %v", err)
+		}
+		if rec["trial_id"] != "trial-2" {
+			t.Errorf("expected trial_id=trial-2, got %v", rec["trial_id"])
+		}
+	})
+
+	t.Run("rotates when the file exceeds max bytes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "trials.jsonl")
+		tl, err := newTrialLogger(path, 40, 2, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatalf("newTrialLogger failed: %v", err)
+		}
+
+		for i := 0; i < 5; i++ {
+			tl.WriteTrial(map[string]interface{}{"trial_id": "trial", "n": i})
+		}
+		tl.Close() // drains the async write queue before we check rotation on disk
+
+		if _, err := os.Stat(path + ".1"); err != nil {
+			t.Errorf("expected rotated file %s.1 to exist: %v", path, err)
+		}
+	})
+
+	t.Run("keeps appending to an existing log file across restarts", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "trials.jsonl")
+		tl, err := newTrialLogger(path, 0, 0, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatalf("newTrialLogger failed: %v", err)
+		}
+		tl.WriteTrial(map[string]interface{}{"trial_id": "trial-1"})
+		tl.Close()
+
+		tl2, err := newTrialLogger(path, 0, 0, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatalf("reopening trial log failed: %v", err)
+		}
+		tl2.WriteTrial(map[string]interface{}{"trial_id": "trial-2"})
+		tl2.Close() // drains the async write queue before we read the file back
+
+		lines := readLines(t, path)
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines after reopen, got %d", len(lines))
+		}
+	})
+
+	t.Run("WriteTrial drops the record rather than blocking when the queue is full", func(t *testing.T) {
+		// Construct directly rather than via newTrialLogger so no writer
+		// goroutine is draining the queue underneath us -- this is the
+		// sustained-slow-disk case WriteTrial must never block on, since its
+		// callers (forceSensor.finalizeLocked) hold fs.mu.
+		tl := &trialLogger{
+			logger: logging.NewTestLogger(t),
+			queue:  make(chan map[string]interface{}, 1),
+			done:   make(chan struct{}),
+		}
+		tl.queue <- map[string]interface{}{"trial_id": "fills-the-queue"}
+
+		returned := make(chan struct{})
+		go func() {
+			tl.WriteTrial(map[string]interface{}{"trial_id": "dropped"})
+			close(returned)
+		}()
+
+		select {
+		case <-returned:
+		case <-time.After(time.Second):
+			t.Fatal("WriteTrial blocked on a full queue instead of dropping the record")
+		}
+	})
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
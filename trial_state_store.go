@@ -0,0 +1,114 @@
+package kettlecycletest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// persistedTrial is the subset of trialState that survives a controller
+// restart -- enough for rehydrateTrial to rebuild activeTrial and resume
+// cycleLoop from where it left off instead of losing the trial entirely.
+type persistedTrial struct {
+	Controller          string    `json:"controller"`
+	TrialID             string    `json:"trial_id"`
+	StartedAt           time.Time `json:"started_at"`
+	LastCycleAt         time.Time `json:"last_cycle_at"`
+	CycleCount          int       `json:"cycle_count"`
+	TargetCycles        int       `json:"target_cycles"`
+	IntervalMs          int       `json:"interval_ms"`
+	MaxDurationMs       int64     `json:"max_duration_ms"`
+	AbortOnError        bool      `json:"abort_on_error"`
+	CurrentCycle        int       `json:"current_cycle"`
+	CompletedCycles     int       `json:"completed_cycles"`
+	FailedCycles        int       `json:"failed_cycles"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Degraded            bool      `json:"degraded"`
+	LostLeadership      bool      `json:"lost_leadership"`
+	TerminalState       string    `json:"terminal_state"`
+}
+
+// TrialStateStore persists the active trial's state so it survives a
+// controller restart. Load returns (nil, nil) if nothing is persisted.
+type TrialStateStore interface {
+	Save(trial *persistedTrial) error
+	Load() (*persistedTrial, error)
+	Clear() error
+}
+
+// jsonFileTrialStateStore is a TrialStateStore backed by a single JSON file
+// at path, keyed by controller so a stale file left over from a different
+// resource name is never mistaken for this controller's trial. Saves are
+// written to a temp file and renamed into place so a crash mid-write can
+// never leave behind a half-written, unparseable file -- unlike
+// trial_store.go and trial_log.go, this file's entire content must be valid
+// on every read, there's no append-and-skip-bad-lines fallback.
+type jsonFileTrialStateStore struct {
+	path       string
+	controller string
+	logger     logging.Logger
+
+	mu sync.Mutex
+}
+
+func newJSONFileTrialStateStore(path, controller string, logger logging.Logger) *jsonFileTrialStateStore {
+	return &jsonFileTrialStateStore{path: path, controller: controller, logger: logger}
+}
+
+func (s *jsonFileTrialStateStore) Save(trial *persistedTrial) error {
+	trial.Controller = s.controller
+
+	data, err := json.Marshal(trial)
+	if err != nil {
+		return fmt.Errorf("marshaling trial state: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing trial state temp file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("renaming trial state temp file: %w", err)
+	}
+	return nil
+}
+
+func (s *jsonFileTrialStateStore) Load() (*persistedTrial, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading trial state file: %w", err)
+	}
+
+	var trial persistedTrial
+	if err := json.Unmarshal(data, &trial); err != nil {
+		return nil, fmt.Errorf("parsing trial state file: %w", err)
+	}
+	if trial.Controller != s.controller {
+		s.logger.Warnf("trial state file %q belongs to controller %q, not %q; ignoring", s.path, trial.Controller, s.controller)
+		return nil, nil
+	}
+	return &trial, nil
+}
+
+func (s *jsonFileTrialStateStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing trial state file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package kettlecycletest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	defaultTrialStoreMaxBytes = 10 * 1024 * 1024 // 10MB
+	defaultTrialStoreMaxFiles = 5
+)
+
+// trialStore persists one JSONL record per trial lifecycle event (start,
+// cycle_completed, cycle_failed, stop, completed, aborted, failed) so a
+// trial's history survives a controller restart. It is distinct from
+// force_sensor's trialLog, which logs per-capture force stats rather than
+// controller-level trial lifecycle events, but rotates the same way: once
+// the active file exceeds maxBytes it is renamed to "<path>.1" (shifting
+// older numbered files up) and a fresh file is opened.
+type trialStore struct {
+	logger   logging.Logger
+	path     string
+	maxBytes int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newTrialStore(path string, logger logging.Logger) (*trialStore, error) {
+	ts := &trialStore{
+		logger:   logger,
+		path:     path,
+		maxBytes: defaultTrialStoreMaxBytes,
+		maxFiles: defaultTrialStoreMaxFiles,
+	}
+	if err := ts.openLocked(); err != nil {
+		return nil, fmt.Errorf("opening trial store %q: %w", path, err)
+	}
+	return ts, nil
+}
+
+func (ts *trialStore) openLocked() error {
+	f, err := os.OpenFile(ts.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	ts.file = f
+	ts.size = info.Size()
+	return nil
+}
+
+// AppendEvent appends record as a single JSON line, fsyncing immediately so
+// the event survives a crash, and rotates the file first if it would grow
+// past maxBytes. Failures are logged but never returned -- a logging problem
+// must never fail a trial.
+func (ts *trialStore) AppendEvent(record map[string]interface{}) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		ts.logger.Warnf("trial store: failed to marshal event: %v", err)
+		return
+	}
+	data = append(data, '\n')
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.file != nil && ts.size+int64(len(data)) > ts.maxBytes {
+		if err := ts.rotateLocked(); err != nil {
+			ts.logger.Warnf("trial store: rotation failed: %v", err)
+		}
+	}
+	if ts.file == nil {
+		ts.logger.Warnf("trial store: no open file, dropping event")
+		return
+	}
+
+	n, err := ts.file.Write(data)
+	if err != nil {
+		ts.logger.Warnf("trial store: write failed: %v", err)
+		return
+	}
+	ts.size += int64(n)
+
+	if err := ts.file.Sync(); err != nil {
+		ts.logger.Warnf("trial store: flush failed: %v", err)
+	}
+}
+
+// rotateLocked shifts "<path>.N" to "<path>.N+1" for N down to 1, dropping
+// anything past maxFiles, then moves the active file to "<path>.1" and opens
+// a fresh one in its place. Callers must hold ts.mu.
+func (ts *trialStore) rotateLocked() error {
+	if ts.file != nil {
+		ts.file.Close()
+		ts.file = nil
+	}
+
+	oldest := fmt.Sprintf("%s.%d", ts.path, ts.maxFiles)
+	os.Remove(oldest)
+
+	for i := ts.maxFiles - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", ts.path, i)
+		dst := fmt.Sprintf("%s.%d", ts.path, i+1)
+		if _, err := os.Stat(src); err == nil {
+			os.Rename(src, dst)
+		}
+	}
+	if _, err := os.Stat(ts.path); err == nil {
+		os.Rename(ts.path, ts.path+".1")
+	}
+
+	return ts.openLocked()
+}
+
+// readAllEvents returns every event recorded across rotated files and the
+// active file, oldest first, flushing the active file before reading it.
+func (ts *trialStore) readAllEvents() ([]map[string]interface{}, error) {
+	ts.mu.Lock()
+	if ts.file != nil {
+		ts.file.Sync()
+	}
+	ts.mu.Unlock()
+
+	var events []map[string]interface{}
+	for i := ts.maxFiles; i >= 1; i-- {
+		evs, err := readEventLines(fmt.Sprintf("%s.%d", ts.path, i))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		events = append(events, evs...)
+	}
+	evs, err := readEventLines(ts.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	events = append(events, evs...)
+	return events, nil
+}
+
+// readEventLines decodes one JSON object per line, skipping blank or
+// malformed lines (e.g. a line torn by a crash mid-write).
+func readEventLines(path string) ([]map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var events []map[string]interface{}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(line, &m); err != nil {
+			continue
+		}
+		events = append(events, m)
+	}
+	return events, nil
+}
+
+// GetTrial returns every recorded event for trialID, oldest first.
+func (ts *trialStore) GetTrial(trialID string) ([]map[string]interface{}, error) {
+	all, err := ts.readAllEvents()
+	if err != nil {
+		return nil, err
+	}
+	var matched []map[string]interface{}
+	for _, e := range all {
+		if id, _ := e["trial_id"].(string); id == trialID {
+			matched = append(matched, e)
+		}
+	}
+	return matched, nil
+}
+
+// trialAggregate is the compact summary ExportTrial computes over a trial's
+// recorded cycle_completed/cycle_failed events.
+type trialAggregate struct {
+	TotalCycles           int     `json:"total_cycles"`
+	FailedCycles          int     `json:"failed_cycles"`
+	MeanCycleDurationMs   float64 `json:"mean_cycle_duration_ms"`
+	MedianCycleDurationMs float64 `json:"median_cycle_duration_ms"`
+	P95CycleDurationMs    float64 `json:"p95_cycle_duration_ms"`
+}
+
+// ExportTrial aggregates every cycle_completed/cycle_failed event recorded
+// for trialID into a compact summary -- total cycles, failure count, and
+// mean/median/p95 cycle duration -- rather than replaying the raw event log
+// (see GetTrial for that).
+func (ts *trialStore) ExportTrial(trialID string) (trialAggregate, error) {
+	events, err := ts.GetTrial(trialID)
+	if err != nil {
+		return trialAggregate{}, err
+	}
+
+	var agg trialAggregate
+	var durationsMs []float64
+	for _, e := range events {
+		event, _ := e["event"].(string)
+		switch event {
+		case "cycle_completed":
+			agg.TotalCycles++
+		case "cycle_failed":
+			agg.TotalCycles++
+			agg.FailedCycles++
+		default:
+			continue
+		}
+		if d, ok := e["duration_ms"].(float64); ok {
+			durationsMs = append(durationsMs, d)
+		}
+	}
+
+	if len(durationsMs) > 0 {
+		sort.Float64s(durationsMs)
+		agg.MeanCycleDurationMs = meanOf(durationsMs)
+		agg.MedianCycleDurationMs = percentileOf(durationsMs, 50)
+		agg.P95CycleDurationMs = percentileOf(durationsMs, 95)
+	}
+
+	return agg, nil
+}
+
+// meanOf returns the arithmetic mean of sorted (or unsorted) values.
+func meanOf(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// percentileOf returns the pct-th percentile of sorted (ascending) values
+// using linear interpolation between the two nearest ranks.
+func percentileOf(sorted []float64, pct float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := pct / 100 * float64(len(sorted)-1)
+	lo := int(rank)
+	hi := lo + 1
+	if hi >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := rank - float64(lo)
+	return sorted[lo] + frac*(sorted[hi]-sorted[lo])
+}
+
+// ListTrials summarizes the last limit trials seen in the store (oldest
+// first within that window), by folding each trial's events into a single
+// summary record. limit <= 0 returns every trial.
+func (ts *trialStore) ListTrials(limit int) ([]map[string]interface{}, error) {
+	all, err := ts.readAllEvents()
+	if err != nil {
+		return nil, err
+	}
+
+	var order []string
+	summaries := map[string]map[string]interface{}{}
+	for _, e := range all {
+		trialID, _ := e["trial_id"].(string)
+		if trialID == "" {
+			continue
+		}
+		summary, ok := summaries[trialID]
+		if !ok {
+			summary = map[string]interface{}{"trial_id": trialID}
+			summaries[trialID] = summary
+			order = append(order, trialID)
+		}
+
+		event, _ := e["event"].(string)
+		summary["last_event"] = event
+		switch event {
+		case "start":
+			summary["start_time"] = e["time"]
+		case "completed", "aborted", "failed":
+			summary["state"] = event
+			summary["end_time"] = e["time"]
+			summary["completed_cycles"] = e["completed_cycles"]
+			summary["failed_cycles"] = e["failed_cycles"]
+		}
+	}
+
+	list := make([]map[string]interface{}, 0, len(order))
+	for _, trialID := range order {
+		list = append(list, summaries[trialID])
+	}
+	if limit > 0 && len(list) > limit {
+		list = list[len(list)-limit:]
+	}
+	return list, nil
+}
+
+// LastTrialSummary returns the summary of the most recently started trial,
+// or nil if the store has no recorded trials yet.
+func (ts *trialStore) LastTrialSummary() (map[string]interface{}, error) {
+	list, err := ts.ListTrials(0)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return list[len(list)-1], nil
+}
+
+func (ts *trialStore) Close() error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.file == nil {
+		return nil
+	}
+	err := ts.file.Close()
+	ts.file = nil
+	return err
+}
@@ -0,0 +1,188 @@
+package kettlecycletest
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestTrialStore(t *testing.T) {
+	t.Run("appends one JSON line per event", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		ts, err := newTrialStore(path, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatalf("newTrialStore failed: %v", err)
+		}
+		defer ts.Close()
+
+		ts.AppendEvent(map[string]interface{}{"event": "start", "trial_id": "trial-1"})
+		ts.AppendEvent(map[string]interface{}{"event": "stop", "trial_id": "trial-1"})
+
+		lines := readLines(t, path)
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines, got %d", len(lines))
+		}
+	})
+
+	t.Run("GetTrial returns only events for the requested trial, in order", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		ts, err := newTrialStore(path, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatalf("newTrialStore failed: %v", err)
+		}
+		defer ts.Close()
+
+		ts.AppendEvent(map[string]interface{}{"event": "start", "trial_id": "trial-1"})
+		ts.AppendEvent(map[string]interface{}{"event": "start", "trial_id": "trial-2"})
+		ts.AppendEvent(map[string]interface{}{"event": "stop", "trial_id": "trial-1"})
+
+		events, err := ts.GetTrial("trial-1")
+		if err != nil {
+			t.Fatalf("GetTrial failed: %v", err)
+		}
+		if len(events) != 2 {
+			t.Fatalf("expected 2 events for trial-1, got %d", len(events))
+		}
+		if events[0]["event"] != "start" || events[1]["event"] != "stop" {
+			t.Errorf("expected [start, stop], got %v", events)
+		}
+	})
+
+	t.Run("ListTrials folds events into one summary per trial", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		ts, err := newTrialStore(path, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatalf("newTrialStore failed: %v", err)
+		}
+		defer ts.Close()
+
+		ts.AppendEvent(map[string]interface{}{"event": "start", "trial_id": "trial-1", "time": "t0"})
+		ts.AppendEvent(map[string]interface{}{"event": "cycle_completed", "trial_id": "trial-1"})
+		ts.AppendEvent(map[string]interface{}{"event": "completed", "trial_id": "trial-1", "time": "t1", "completed_cycles": 1})
+
+		trials, err := ts.ListTrials(0)
+		if err != nil {
+			t.Fatalf("ListTrials failed: %v", err)
+		}
+		if len(trials) != 1 {
+			t.Fatalf("expected 1 trial summary, got %d", len(trials))
+		}
+		summary := trials[0]
+		if summary["state"] != "completed" {
+			t.Errorf("expected state=completed, got %v", summary["state"])
+		}
+		if summary["start_time"] != "t0" {
+			t.Errorf("expected start_time=t0, got %v", summary["start_time"])
+		}
+		if summary["end_time"] != "t1" {
+			t.Errorf("expected end_time=t1, got %v", summary["end_time"])
+		}
+	})
+
+	t.Run("ListTrials with a limit returns only the most recent N trials", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		ts, err := newTrialStore(path, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatalf("newTrialStore failed: %v", err)
+		}
+		defer ts.Close()
+
+		for i := 1; i <= 3; i++ {
+			trialID := fmt.Sprintf("trial-%d", i)
+			ts.AppendEvent(map[string]interface{}{"event": "start", "trial_id": trialID})
+			ts.AppendEvent(map[string]interface{}{"event": "completed", "trial_id": trialID})
+		}
+
+		trials, err := ts.ListTrials(2)
+		if err != nil {
+			t.Fatalf("ListTrials failed: %v", err)
+		}
+		if len(trials) != 2 {
+			t.Fatalf("expected 2 trial summaries with limit=2, got %d", len(trials))
+		}
+		if trials[0]["trial_id"] != "trial-2" || trials[1]["trial_id"] != "trial-3" {
+			t.Errorf("expected the last 2 trials (trial-2, trial-3), got %v, %v", trials[0]["trial_id"], trials[1]["trial_id"])
+		}
+	})
+
+	t.Run("ExportTrial aggregates cycle count, failures, and duration percentiles", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		ts, err := newTrialStore(path, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatalf("newTrialStore failed: %v", err)
+		}
+		defer ts.Close()
+
+		ts.AppendEvent(map[string]interface{}{"event": "start", "trial_id": "trial-1"})
+		ts.AppendEvent(map[string]interface{}{"event": "cycle_completed", "trial_id": "trial-1", "duration_ms": 100})
+		ts.AppendEvent(map[string]interface{}{"event": "cycle_completed", "trial_id": "trial-1", "duration_ms": 200})
+		ts.AppendEvent(map[string]interface{}{"event": "cycle_failed", "trial_id": "trial-1", "duration_ms": 300, "error": "switch error"})
+		ts.AppendEvent(map[string]interface{}{"event": "failed", "trial_id": "trial-1"})
+
+		agg, err := ts.ExportTrial("trial-1")
+		if err != nil {
+			t.Fatalf("ExportTrial failed: %v", err)
+		}
+		if agg.TotalCycles != 3 {
+			t.Errorf("expected total_cycles=3, got %d", agg.TotalCycles)
+		}
+		if agg.FailedCycles != 1 {
+			t.Errorf("expected failed_cycles=1, got %d", agg.FailedCycles)
+		}
+		if !approxEqual(agg.MeanCycleDurationMs, 200, 0.001) {
+			t.Errorf("expected mean_cycle_duration_ms=200, got %v", agg.MeanCycleDurationMs)
+		}
+		if !approxEqual(agg.MedianCycleDurationMs, 200, 0.001) {
+			t.Errorf("expected median_cycle_duration_ms=200, got %v", agg.MedianCycleDurationMs)
+		}
+		if !approxEqual(agg.P95CycleDurationMs, 290, 0.001) {
+			t.Errorf("expected p95_cycle_duration_ms=290, got %v", agg.P95CycleDurationMs)
+		}
+	})
+
+	t.Run("ExportTrial of a trial with no cycles returns a zero aggregate", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		ts, err := newTrialStore(path, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatalf("newTrialStore failed: %v", err)
+		}
+		defer ts.Close()
+
+		ts.AppendEvent(map[string]interface{}{"event": "start", "trial_id": "trial-1"})
+
+		agg, err := ts.ExportTrial("trial-1")
+		if err != nil {
+			t.Fatalf("ExportTrial failed: %v", err)
+		}
+		if agg.TotalCycles != 0 || agg.MeanCycleDurationMs != 0 {
+			t.Errorf("expected a zero aggregate, got %+v", agg)
+		}
+	})
+
+	t.Run("events survive a store restart against the same file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.jsonl")
+		ts, err := newTrialStore(path, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatalf("newTrialStore failed: %v", err)
+		}
+		ts.AppendEvent(map[string]interface{}{"event": "start", "trial_id": "trial-1"})
+		ts.Close()
+
+		ts2, err := newTrialStore(path, logging.NewTestLogger(t))
+		if err != nil {
+			t.Fatalf("reopening trial store failed: %v", err)
+		}
+		defer ts2.Close()
+		ts2.AppendEvent(map[string]interface{}{"event": "stop", "trial_id": "trial-1"})
+
+		events, err := ts2.GetTrial("trial-1")
+		if err != nil {
+			t.Fatalf("GetTrial failed: %v", err)
+		}
+		if len(events) != 2 {
+			t.Fatalf("expected 2 events after reopen, got %d", len(events))
+		}
+	})
+}